@@ -0,0 +1,62 @@
+package keycloak
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// BruteForceStatus is the brute force detection status of a user.
+type BruteForceStatus struct {
+	NumFailures *int `json:"numFailures,omitempty"`
+	// Disabled is true if the user is temporarily locked out.
+	Disabled *bool `json:"disabled,omitempty"`
+	// LastFailure is the time of the last failed login in milliseconds since
+	// the epoch.
+	LastFailure   *int64  `json:"lastFailure,omitempty"`
+	LastIPFailure *string `json:"lastIPFailure,omitempty"`
+}
+
+// AttackDetectionService handles communication with the attack detection related methods of the Keycloak API.
+type AttackDetectionService service
+
+// GetBruteForceStatus returns the brute force detection status of the user.
+func (s *AttackDetectionService) GetBruteForceStatus(ctx context.Context, realm, userID string) (*BruteForceStatus, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/attack-detection/brute-force/users/%s", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var status BruteForceStatus
+	res, err := s.keycloak.Do(ctx, req, &status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &status, res, nil
+}
+
+// ClearBruteForce clears the login failures of the user, which unlocks a
+// temporarily locked user.
+func (s *AttackDetectionService) ClearBruteForce(ctx context.Context, realm, userID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/attack-detection/brute-force/users/%s", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ClearAllBruteForce clears the login failures of all users of the realm,
+// which unlocks all temporarily locked users.
+func (s *AttackDetectionService) ClearAllBruteForce(ctx context.Context, realm string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/attack-detection/brute-force/users", realm)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
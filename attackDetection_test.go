@@ -0,0 +1,81 @@
+package keycloak
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAttackDetectionService_GetBruteForceStatus(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	ctx := context.Background()
+
+	if _, err := k.Realms.Create(ctx, &Realm{
+		Enabled:             Bool(true),
+		Realm:               String(realm),
+		BruteForceProtected: Bool(true),
+	}); err != nil {
+		t.Fatalf("Realms.Create returned error: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := k.Realms.Delete(ctx, realm); err != nil {
+			t.Errorf("Realms.Delete returned error: %v", err)
+		}
+	})
+
+	userID := createUser(t, k, realm, "user")
+	login(t, k, realm, userID, "user")
+
+	if _, err := NewWithPassword(ctx, "http://localhost:8080/", realm, "admin-cli", "user", "wrong"); err == nil {
+		t.Errorf("got: nil, want: error")
+	}
+
+	status, res, err := k.AttackDetection.GetBruteForceStatus(ctx, realm, userID)
+	if err != nil {
+		t.Fatalf("AttackDetection.GetBruteForceStatus returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *status.NumFailures != 1 {
+		t.Errorf("got: %d, want: %d", *status.NumFailures, 1)
+	}
+
+	res, err = k.AttackDetection.ClearBruteForce(ctx, realm, userID)
+	if err != nil {
+		t.Errorf("AttackDetection.ClearBruteForce returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	status, _, err = k.AttackDetection.GetBruteForceStatus(ctx, realm, userID)
+	if err != nil {
+		t.Fatalf("AttackDetection.GetBruteForceStatus returned error: %v", err)
+	}
+
+	if *status.NumFailures != 0 {
+		t.Errorf("got: %d, want: %d", *status.NumFailures, 0)
+	}
+}
+
+func TestAttackDetectionService_ClearAllBruteForce(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	res, err := k.AttackDetection.ClearAllBruteForce(context.Background(), realm)
+	if err != nil {
+		t.Errorf("AttackDetection.ClearAllBruteForce returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+}
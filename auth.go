@@ -0,0 +1,112 @@
+package keycloak
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenURL returns the OpenID Connect token endpoint of realm.
+func tokenURL(baseURL, realm string) string {
+	return fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(baseURL, "/"), realm)
+}
+
+// NewWithPassword returns a new Keycloak instance which authenticates with
+// the resource owner password credentials grant, e.g. as "admin" user with
+// the "admin-cli" client in the "master" realm.
+//
+// Access tokens are refreshed automatically. When the refresh token has
+// expired as well, e.g. after the SSO session max, the instance logs in
+// again with username and password.
+//
+// ctx is used for all token requests and must stay valid as long as the
+// instance is used.
+func NewWithPassword(ctx context.Context, baseURL, realm, clientID, username, password string) (*Keycloak, error) {
+	config := &oauth2.Config{
+		ClientID: clientID,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  tokenURL(baseURL, realm),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		Scopes: []string{"openid"},
+	}
+
+	ts := &passwordTokenSource{
+		ctx:      ctx,
+		config:   config,
+		username: username,
+		password: password,
+	}
+
+	// log in right away to catch wrong credentials early
+	if _, err := ts.Token(); err != nil {
+		return nil, err
+	}
+
+	return NewKeycloak(oauth2.NewClient(ctx, ts), baseURL)
+}
+
+// NewWithClientCredentials returns a new Keycloak instance which
+// authenticates with the client credentials grant, i.e. as service account
+// of a confidential client. A new access token is requested whenever the
+// current one expires.
+//
+// ctx is used for all token requests and must stay valid as long as the
+// instance is used.
+func NewWithClientCredentials(ctx context.Context, baseURL, realm, clientID, clientSecret string) (*Keycloak, error) {
+	config := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL(baseURL, realm),
+	}
+
+	ts := config.TokenSource(ctx)
+
+	// log in right away to catch wrong credentials early
+	if _, err := ts.Token(); err != nil {
+		return nil, err
+	}
+
+	return NewKeycloak(oauth2.NewClient(ctx, ts), baseURL)
+}
+
+// passwordTokenSource is an oauth2.TokenSource which refreshes the token and
+// falls back to the password grant if the refresh fails.
+type passwordTokenSource struct {
+	ctx      context.Context
+	config   *oauth2.Config
+	username string
+	password string
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// Token returns a valid token.
+func (s *passwordTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Valid() {
+		return s.token, nil
+	}
+
+	if s.token != nil && s.token.RefreshToken != "" {
+		token, err := s.config.TokenSource(s.ctx, s.token).Token()
+		if err == nil {
+			s.token = token
+			return token, nil
+		}
+	}
+
+	token, err := s.config.PasswordCredentialsToken(s.ctx, s.username, s.password)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
@@ -0,0 +1,122 @@
+package keycloak
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// create a fake token endpoint which counts the requested grant types.
+func tokenServer(t *testing.T, grants map[string]int, refresh bool) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/realms" {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+			return
+		}
+
+		if r.URL.Path != "/realms/master/protocol/openid-connect/token" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		r.ParseForm()
+		grant := r.PostForm.Get("grant_type")
+
+		mu.Lock()
+		grants[grant]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case grant == "password" && r.PostForm.Get("password") != "admin":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Invalid user credentials"}`)
+		case grant == "refresh_token" && !refresh:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Session not active"}`)
+		case grant == "client_credentials" && r.PostForm.Get("client_secret") == "" && r.Header.Get("Authorization") == "":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			// the token expires immediately so every request needs a new one
+			fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":1,"refresh_token":"refresh"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewWithPassword(t *testing.T) {
+	grants := map[string]int{}
+	server := tokenServer(t, grants, true)
+
+	ctx := context.Background()
+	k, err := NewWithPassword(ctx, server.URL+"/", "master", "admin-cli", "admin", "admin")
+	if err != nil {
+		t.Fatalf("NewWithPassword returned error: %v", err)
+	}
+
+	if _, _, err := k.Realms.List(ctx); err != nil {
+		t.Errorf("Realms.List returned error: %v", err)
+	}
+
+	if grants["password"] != 1 || grants["refresh_token"] != 1 {
+		t.Errorf("got: %v, want: one password and one refresh_token grant", grants)
+	}
+}
+
+func TestNewWithPassword_refreshFails(t *testing.T) {
+	grants := map[string]int{}
+	server := tokenServer(t, grants, false)
+
+	ctx := context.Background()
+	k, err := NewWithPassword(ctx, server.URL+"/", "master", "admin-cli", "admin", "admin")
+	if err != nil {
+		t.Fatalf("NewWithPassword returned error: %v", err)
+	}
+
+	if _, _, err := k.Realms.List(ctx); err != nil {
+		t.Errorf("Realms.List returned error: %v", err)
+	}
+
+	// log in again after the refresh failed
+	if grants["password"] != 2 || grants["refresh_token"] != 1 {
+		t.Errorf("got: %v, want: two password and one refresh_token grant", grants)
+	}
+}
+
+func TestNewWithPassword_invalidCredentials(t *testing.T) {
+	server := tokenServer(t, map[string]int{}, true)
+
+	_, err := NewWithPassword(context.Background(), server.URL+"/", "master", "admin-cli", "admin", "wrong")
+	if err == nil {
+		t.Error("NewWithPassword returned no error for invalid credentials")
+	}
+}
+
+func TestNewWithClientCredentials(t *testing.T) {
+	grants := map[string]int{}
+	server := tokenServer(t, grants, true)
+
+	ctx := context.Background()
+	k, err := NewWithClientCredentials(ctx, server.URL+"/", "master", "myclient", "secret")
+	if err != nil {
+		t.Fatalf("NewWithClientCredentials returned error: %v", err)
+	}
+
+	if _, _, err := k.Realms.List(ctx); err != nil {
+		t.Errorf("Realms.List returned error: %v", err)
+	}
+
+	if grants["client_credentials"] != 2 {
+		t.Errorf("got: %v, want: two client_credentials grants", grants)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // ClientRolesService handles communication with the client roles related methods of the Keycloak API.
@@ -21,8 +22,13 @@ func (s *ClientRolesService) Create(ctx context.Context, realm, id string, role
 }
 
 // List lists all client roles.
-func (s *ClientRolesService) List(ctx context.Context, realm, id string) ([]*Role, *http.Response, error) {
+func (s *ClientRolesService) List(ctx context.Context, realm, id string, opts *Options) ([]*Role, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/roles", realm, id)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -37,9 +43,16 @@ func (s *ClientRolesService) List(ctx context.Context, realm, id string) ([]*Rol
 	return roles, res, nil
 }
 
+// Pager returns a Pager which iterates over all client roles.
+func (s *ClientRolesService) Pager(realm, id string, opts *Options) *Pager[*Role] {
+	return NewPager(opts, func(ctx context.Context, opts *Options) ([]*Role, *http.Response, error) {
+		return s.List(ctx, realm, id, opts)
+	})
+}
+
 // Get retrieves a single client role.
 func (s *ClientRolesService) Get(ctx context.Context, realm, id, roleName string) (*Role, *http.Response, error) {
-	u := fmt.Sprintf("admin/realms/%s/clients/%s/roles/%s", realm, id, roleName)
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/roles/%s", realm, id, url.PathEscape(roleName))
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -56,7 +56,7 @@ func TestClientRolesService_List(t *testing.T) {
 	createClientRole(t, k, realm, clientID, "first")
 	createClientRole(t, k, realm, clientID, "second")
 
-	roles, res, err := k.ClientRoles.List(context.Background(), realm, clientID)
+	roles, res, err := k.ClientRoles.List(context.Background(), realm, clientID, nil)
 	if err != nil {
 		t.Errorf("ClientRoles.List returned error: %v", err)
 	}
@@ -10,11 +10,12 @@ import (
 //
 // https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/ClientScopeRepresentation.java
 type ClientScope struct {
-	ID          *string            `json:"id,omitempty"`
-	Name        *string            `json:"name,omitempty"`
-	Description *string            `json:"description,omitempty"`
-	Protocol    *string            `json:"protocol,omitempty"`
-	Attributes  *map[string]string `json:"attributes,omitempty"`
+	ID              *string            `json:"id,omitempty"`
+	Name            *string            `json:"name,omitempty"`
+	Description     *string            `json:"description,omitempty"`
+	Protocol        *string            `json:"protocol,omitempty"`
+	Attributes      *map[string]string `json:"attributes,omitempty"`
+	ProtocolMappers []*ProtocolMapper  `json:"protocolMappers,omitempty"`
 }
 
 // ClientScopesService ...
@@ -48,6 +49,49 @@ func (s *ClientScopesService) Create(ctx context.Context, realm string, clientSc
 	return s.keycloak.Do(ctx, req, nil)
 }
 
+// CreateAndGet creates a new client scope and returns the created client scope with all its properties.
+func (s *ClientScopesService) CreateAndGet(ctx context.Context, realm string, clientScope *ClientScope) (*ClientScope, *http.Response, error) {
+	res, err := s.Create(ctx, realm, clientScope)
+	if err != nil {
+		return nil, res, err
+	}
+
+	id, err := IDFromLocation(res)
+	if err != nil {
+		return nil, res, err
+	}
+
+	return s.Get(ctx, realm, id)
+}
+
+// GetByName gets the client scope with the given name. It returns an error
+// wrapping ErrNotFound if there is no such client scope.
+func (s *ClientScopesService) GetByName(ctx context.Context, realm, name string) (*ClientScope, *http.Response, error) {
+	clientScopes, res, err := s.List(ctx, realm)
+	if err != nil {
+		return nil, res, err
+	}
+
+	for _, clientScope := range clientScopes {
+		if clientScope.Name != nil && *clientScope.Name == name {
+			return clientScope, res, nil
+		}
+	}
+
+	return nil, res, fmt.Errorf("keycloak: client scope %q: %w", name, ErrNotFound)
+}
+
+// Update client scope.
+func (s *ClientScopesService) Update(ctx context.Context, realm string, clientScope *ClientScope) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s", realm, *clientScope.ID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, clientScope)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
 // Get client scope.
 func (s *ClientScopesService) Get(ctx context.Context, realm, clientScopeID string) (*ClientScope, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s", realm, clientScopeID)
@@ -75,3 +119,81 @@ func (s *ClientScopesService) Delete(ctx context.Context, realm, clientScopeID s
 
 	return s.keycloak.Do(ctx, req, nil)
 }
+
+// CreateProtocolMapper creates a new protocol mapper for the client scope.
+func (s *ClientScopesService) CreateProtocolMapper(ctx context.Context, realm, clientScopeID string, mapper *ProtocolMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s/protocol-mappers/models", realm, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// AddProtocolMappers creates multiple protocol mappers for the client scope at once.
+func (s *ClientScopesService) AddProtocolMappers(ctx context.Context, realm, clientScopeID string, mappers []*ProtocolMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s/protocol-mappers/add-models", realm, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, mappers)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListProtocolMappers lists all protocol mappers of the client scope.
+func (s *ClientScopesService) ListProtocolMappers(ctx context.Context, realm, clientScopeID string) ([]*ProtocolMapper, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s/protocol-mappers/models", realm, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mappers []*ProtocolMapper
+	res, err := s.keycloak.Do(ctx, req, &mappers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mappers, res, nil
+}
+
+// GetProtocolMapper gets a single protocol mapper of the client scope.
+func (s *ClientScopesService) GetProtocolMapper(ctx context.Context, realm, clientScopeID, mapperID string) (*ProtocolMapper, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s/protocol-mappers/models/%s", realm, clientScopeID, mapperID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mapper ProtocolMapper
+	res, err := s.keycloak.Do(ctx, req, &mapper)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mapper, res, nil
+}
+
+// UpdateProtocolMapper updates a protocol mapper of the client scope.
+func (s *ClientScopesService) UpdateProtocolMapper(ctx context.Context, realm, clientScopeID string, mapper *ProtocolMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s/protocol-mappers/models/%s", realm, clientScopeID, *mapper.ID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// DeleteProtocolMapper deletes a protocol mapper of the client scope.
+func (s *ClientScopesService) DeleteProtocolMapper(ctx context.Context, realm, clientScopeID, mapperID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/client-scopes/%s/protocol-mappers/models/%s", realm, clientScopeID, mapperID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
@@ -3,7 +3,6 @@ package keycloak
 import (
 	"context"
 	"net/http"
-	"strings"
 	"testing"
 )
 
@@ -21,8 +20,10 @@ func createClientScope(t *testing.T, k *Keycloak, realm string, clientScopeName
 		t.Errorf("ClientScopes.Create returned error: %v", err)
 	}
 
-	parts := strings.Split(res.Header.Get("Location"), "/")
-	clientScopeID := parts[len(parts)-1]
+	clientScopeID, err := IDFromLocation(res)
+	if err != nil {
+		t.Errorf("IDFromLocation returned error: %v", err)
+	}
 	return clientScopeID
 }
 
@@ -70,6 +71,30 @@ func TestClientScopesService_Create(t *testing.T) {
 	}
 }
 
+func TestClientScopesService_CreateAndGet(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientScope := &ClientScope{
+		Name: String("scope"),
+	}
+
+	clientScope, res, err := k.ClientScopes.CreateAndGet(context.Background(), realm, clientScope)
+	if err != nil {
+		t.Errorf("ClientScopes.CreateAndGet returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *clientScope.Name != "scope" {
+		t.Errorf("got: %s, want: %s", *clientScope.Name, "scope")
+	}
+}
+
 func TestClientScopesService_Get(t *testing.T) {
 	k := client(t)
 
@@ -109,3 +134,91 @@ func TestClientScopesService_Delete(t *testing.T) {
 		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
 	}
 }
+
+func TestClientScopesService_AddProtocolMappers(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientScopeID := createClientScope(t, k, realm, "scope")
+
+	mappers := []*ProtocolMapper{
+		NewHardcodedClaimMapper("tenant", "tenant", "acme"),
+		NewUserAttributeMapper("department", "department", "department"),
+	}
+
+	res, err := k.ClientScopes.AddProtocolMappers(context.Background(), realm, clientScopeID, mappers)
+	if err != nil {
+		t.Errorf("ClientScopes.AddProtocolMappers returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	list, _, err := k.ClientScopes.ListProtocolMappers(context.Background(), realm, clientScopeID)
+	if err != nil {
+		t.Errorf("ClientScopes.ListProtocolMappers returned error: %v", err)
+	}
+
+	if len(list) != 2 {
+		t.Errorf("got: %d, want: %d", len(list), 2)
+	}
+}
+
+func TestClientScopesService_GetByName(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientScopeID := createClientScope(t, k, realm, "scope")
+
+	clientScope, _, err := k.ClientScopes.GetByName(context.Background(), realm, "scope")
+	if err != nil {
+		t.Errorf("ClientScopes.GetByName returned error: %v", err)
+	}
+
+	if *clientScope.ID != clientScopeID {
+		t.Errorf("got: %s, want: %s", *clientScope.ID, clientScopeID)
+	}
+
+	_, _, err = k.ClientScopes.GetByName(context.Background(), realm, "unknown")
+	if !IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestClientScopesService_Update(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientScopeID := createClientScope(t, k, realm, "scope")
+
+	clientScope := &ClientScope{
+		ID:          String(clientScopeID),
+		Name:        String("scope"),
+		Description: String("updated"),
+	}
+
+	res, err := k.ClientScopes.Update(context.Background(), realm, clientScope)
+	if err != nil {
+		t.Errorf("ClientScopes.Update returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	clientScope, _, err = k.ClientScopes.Get(context.Background(), realm, clientScopeID)
+	if err != nil {
+		t.Errorf("ClientScopes.Get returned error: %v", err)
+	}
+
+	if *clientScope.Description != "updated" {
+		t.Errorf("got: %s, want: %s", *clientScope.Description, "updated")
+	}
+}
@@ -22,6 +22,7 @@ type Client struct {
 	Enabled                            *bool              `json:"enabled,omitempty"`
 	AlwaysDisplayInConsole             *bool              `json:"alwaysDisplayInConsole,omitempty"`
 	ClientAuthenticatorType            *string            `json:"clientAuthenticatorType,omitempty"`
+	Secret                             *string            `json:"secret,omitempty"`
 	DefaultRoles                       []string           `json:"defaultRoles,omitempty"`
 	RedirectUris                       []string           `json:"redirectUris,omitempty"`
 	WebOrigins                         []string           `json:"webOrigins,omitempty"`
@@ -43,11 +44,25 @@ type Client struct {
 	DefaultClientScopes                []string           `json:"defaultClientScopes,omitempty"`
 	OptionalClientScopes               []string           `json:"optionalClientScopes,omitempty"`
 	Access                             *map[string]bool   `json:"access,omitempty"`
+	ProtocolMappers                    []*ProtocolMapper  `json:"protocolMappers,omitempty"`
+}
+
+// ClientsListOptions ...
+type ClientsListOptions struct {
+	ClientID     string `url:"clientId,omitempty"`
+	Search       bool   `url:"search,omitempty"`
+	ViewableOnly bool   `url:"viewableOnly,omitempty"`
+	Options
 }
 
 // List all clients in realm.
-func (s *ClientsService) List(ctx context.Context, realm string) ([]*Client, *http.Response, error) {
+func (s *ClientsService) List(ctx context.Context, realm string, opts *ClientsListOptions) ([]*Client, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients", realm)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -62,6 +77,19 @@ func (s *ClientsService) List(ctx context.Context, realm string) ([]*Client, *ht
 	return clients, res, nil
 }
 
+// Pager returns a Pager which iterates over all clients.
+func (s *ClientsService) Pager(realm string, opts *ClientsListOptions) *Pager[*Client] {
+	var filter ClientsListOptions
+	if opts != nil {
+		filter = *opts
+	}
+	return NewPager(&filter.Options, func(ctx context.Context, page *Options) ([]*Client, *http.Response, error) {
+		o := filter
+		o.Options = *page
+		return s.List(ctx, realm, &o)
+	})
+}
+
 // Create a new client.
 func (s *ClientsService) Create(ctx context.Context, realm string, client *Client) (*http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients", realm)
@@ -73,6 +101,21 @@ func (s *ClientsService) Create(ctx context.Context, realm string, client *Clien
 	return s.keycloak.Do(ctx, req, nil)
 }
 
+// CreateAndGet creates a new client and returns the created client with all its properties.
+func (s *ClientsService) CreateAndGet(ctx context.Context, realm string, client *Client) (*Client, *http.Response, error) {
+	res, err := s.Create(ctx, realm, client)
+	if err != nil {
+		return nil, res, err
+	}
+
+	id, err := IDFromLocation(res)
+	if err != nil {
+		return nil, res, err
+	}
+
+	return s.Get(ctx, realm, id)
+}
+
 // Update a new client.
 func (s *ClientsService) Update(ctx context.Context, realm string, client *Client) (*http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s", realm, *client.ID)
@@ -101,9 +144,33 @@ func (s *ClientsService) Get(ctx context.Context, realm, id string) (*Client, *h
 	return &client, res, nil
 }
 
-// Delete ...
-func (s *ClientsService) Delete() {
+// GetByClientID gets the ID of the client with the given client ID, i.e. the
+// human-readable name like "account". It returns an error wrapping ErrNotFound
+// if there is no such client.
+func (s *ClientsService) GetByClientID(ctx context.Context, realm, clientID string) (string, *http.Response, error) {
+	clients, res, err := s.List(ctx, realm, &ClientsListOptions{ClientID: clientID})
+	if err != nil {
+		return "", res, err
+	}
+
+	for _, client := range clients {
+		if client.ClientID != nil && *client.ClientID == clientID && client.ID != nil {
+			return *client.ID, res, nil
+		}
+	}
+
+	return "", res, fmt.Errorf("keycloak: client %q: %w", clientID, ErrNotFound)
+}
+
+// Delete client.
+func (s *ClientsService) Delete(ctx context.Context, realm, id string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s", realm, id)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
 
+	return s.keycloak.Do(ctx, req, nil)
 }
 
 // GetSecret gets client secret.
@@ -140,8 +207,229 @@ func (s *ClientsService) CreateSecret(ctx context.Context, realm, id string) (*C
 	return &credential, res, nil
 }
 
-// Options ...
-type Options struct {
-	First int    `url:"first,omitempty"`
-	Max   string `url:"max,omitempty"`
+// GetServiceAccountUser gets the service account user of the client. The client
+// must have ServiceAccountsEnabled set.
+func (s *ClientsService) GetServiceAccountUser(ctx context.Context, realm, id string) (*User, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/service-account-user", realm, id)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var user User
+	res, err := s.keycloak.Do(ctx, req, &user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &user, res, nil
+}
+
+// AddServiceAccountRealmRoles adds the realm roles with the given names to the
+// service account user of the client.
+func (s *ClientsService) AddServiceAccountRealmRoles(ctx context.Context, realm, id string, roleNames ...string) (*http.Response, error) {
+	user, res, err := s.GetServiceAccountUser(ctx, realm, id)
+	if err != nil {
+		return res, err
+	}
+
+	roles := make([]*Role, 0, len(roleNames))
+	for _, name := range roleNames {
+		role, res, err := s.keycloak.RealmRoles.GetByName(ctx, realm, name)
+		if err != nil {
+			return res, err
+		}
+		roles = append(roles, role)
+	}
+
+	return s.keycloak.Users.AddRealmRoles(ctx, realm, *user.ID, roles)
+}
+
+// AddServiceAccountClientRoles adds the roles with the given names of the client
+// clientID to the service account user of the client id. Both IDs are the
+// internal IDs of the clients and not their client IDs.
+func (s *ClientsService) AddServiceAccountClientRoles(ctx context.Context, realm, id, clientID string, roleNames ...string) (*http.Response, error) {
+	user, res, err := s.GetServiceAccountUser(ctx, realm, id)
+	if err != nil {
+		return res, err
+	}
+
+	roles := make([]*Role, 0, len(roleNames))
+	for _, name := range roleNames {
+		role, res, err := s.keycloak.ClientRoles.Get(ctx, realm, clientID, name)
+		if err != nil {
+			return res, err
+		}
+		roles = append(roles, role)
+	}
+
+	return s.keycloak.Users.AddClientRoles(ctx, realm, *user.ID, clientID, roles)
+}
+
+// AddServiceAccountRealmManagementRoles adds the roles with the given names of
+// the "realm-management" client, e.g. "view-users" or "manage-clients", to the
+// service account user of the client.
+func (s *ClientsService) AddServiceAccountRealmManagementRoles(ctx context.Context, realm, id string, roleNames ...string) (*http.Response, error) {
+	clientID, res, err := s.GetByClientID(ctx, realm, "realm-management")
+	if err != nil {
+		return res, err
+	}
+
+	return s.AddServiceAccountClientRoles(ctx, realm, id, clientID, roleNames...)
+}
+
+// CreateProtocolMapper creates a new protocol mapper for the client.
+func (s *ClientsService) CreateProtocolMapper(ctx context.Context, realm, id string, mapper *ProtocolMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/protocol-mappers/models", realm, id)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// AddProtocolMappers creates multiple protocol mappers for the client at once.
+func (s *ClientsService) AddProtocolMappers(ctx context.Context, realm, id string, mappers []*ProtocolMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/protocol-mappers/add-models", realm, id)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, mappers)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListProtocolMappers lists all protocol mappers of the client.
+func (s *ClientsService) ListProtocolMappers(ctx context.Context, realm, id string) ([]*ProtocolMapper, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/protocol-mappers/models", realm, id)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mappers []*ProtocolMapper
+	res, err := s.keycloak.Do(ctx, req, &mappers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mappers, res, nil
+}
+
+// GetProtocolMapper gets a single protocol mapper of the client.
+func (s *ClientsService) GetProtocolMapper(ctx context.Context, realm, id, mapperID string) (*ProtocolMapper, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/protocol-mappers/models/%s", realm, id, mapperID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mapper ProtocolMapper
+	res, err := s.keycloak.Do(ctx, req, &mapper)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mapper, res, nil
+}
+
+// UpdateProtocolMapper updates a protocol mapper of the client.
+func (s *ClientsService) UpdateProtocolMapper(ctx context.Context, realm, id string, mapper *ProtocolMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/protocol-mappers/models/%s", realm, id, *mapper.ID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// DeleteProtocolMapper deletes a protocol mapper of the client.
+func (s *ClientsService) DeleteProtocolMapper(ctx context.Context, realm, id, mapperID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/protocol-mappers/models/%s", realm, id, mapperID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListDefaultClientScopes lists the default client scopes of the client.
+func (s *ClientsService) ListDefaultClientScopes(ctx context.Context, realm, id string) ([]*ClientScope, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/default-client-scopes", realm, id)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var clientScopes []*ClientScope
+	res, err := s.keycloak.Do(ctx, req, &clientScopes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return clientScopes, res, nil
+}
+
+// AddDefaultClientScope adds the client scope to the default client scopes of the client.
+func (s *ClientsService) AddDefaultClientScope(ctx context.Context, realm, id, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/default-client-scopes/%s", realm, id, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// RemoveDefaultClientScope removes the client scope from the default client scopes of the client.
+func (s *ClientsService) RemoveDefaultClientScope(ctx context.Context, realm, id, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/default-client-scopes/%s", realm, id, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListOptionalClientScopes lists the optional client scopes of the client.
+func (s *ClientsService) ListOptionalClientScopes(ctx context.Context, realm, id string) ([]*ClientScope, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/optional-client-scopes", realm, id)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var clientScopes []*ClientScope
+	res, err := s.keycloak.Do(ctx, req, &clientScopes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return clientScopes, res, nil
+}
+
+// AddOptionalClientScope adds the client scope to the optional client scopes of the client.
+func (s *ClientsService) AddOptionalClientScope(ctx context.Context, realm, id, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/optional-client-scopes/%s", realm, id, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// RemoveOptionalClientScope removes the client scope from the optional client scopes of the client.
+func (s *ClientsService) RemoveOptionalClientScope(ctx context.Context, realm, id, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/clients/%s/optional-client-scopes/%s", realm, id, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
 }
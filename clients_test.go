@@ -3,7 +3,6 @@ package keycloak
 import (
 	"context"
 	"net/http"
-	"strings"
 	"testing"
 )
 
@@ -25,8 +24,10 @@ func createClient(t *testing.T, k *Keycloak, realm string, clientID string) stri
 		t.Errorf("Clients.Create returned error: %v", err)
 	}
 
-	parts := strings.Split(res.Header.Get("Location"), "/")
-	id := parts[len(parts)-1]
+	id, err := IDFromLocation(res)
+	if err != nil {
+		t.Errorf("IDFromLocation returned error: %v", err)
+	}
 	return id
 }
 
@@ -53,13 +54,42 @@ func TestClientsService_Create(t *testing.T) {
 	}
 }
 
+func TestClientsService_CreateAndGet(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	client := &Client{
+		Enabled:  Bool(true),
+		ClientID: String("myclient"),
+	}
+
+	client, res, err := k.Clients.CreateAndGet(context.Background(), realm, client)
+	if err != nil {
+		t.Errorf("Clients.CreateAndGet returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if client.ID == nil {
+		t.Errorf("got: %v, want: client ID", client.ID)
+	}
+
+	if *client.ClientID != "myclient" {
+		t.Errorf("got: %s, want: %s", *client.ClientID, "myclient")
+	}
+}
+
 func TestClientsService_List(t *testing.T) {
 	k := client(t)
 
 	realm := "first"
 	createRealm(t, k, realm)
 
-	clients, res, err := k.Clients.List(context.Background(), realm)
+	clients, res, err := k.Clients.List(context.Background(), realm, nil)
 	if err != nil {
 		t.Errorf("Clients.List returned error: %v", err)
 	}
@@ -74,6 +104,54 @@ func TestClientsService_List(t *testing.T) {
 	}
 }
 
+func TestClientsService_List_clientID(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	opts := &ClientsListOptions{
+		ClientID: "account",
+		Search:   true,
+	}
+	clients, _, err := k.Clients.List(context.Background(), realm, opts)
+	if err != nil {
+		t.Errorf("Clients.List returned error: %v", err)
+	}
+
+	// it includes the "account" and "account-console"
+	if len(clients) != 2 {
+		t.Errorf("got: %d, want: %d", len(clients), 2)
+	}
+}
+
+func TestClientsService_GetByClientID(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+
+	id, res, err := k.Clients.GetByClientID(context.Background(), realm, "client")
+	if err != nil {
+		t.Errorf("Clients.GetByClientID returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if id != clientID {
+		t.Errorf("got: %s, want: %s", id, clientID)
+	}
+
+	_, _, err = k.Clients.GetByClientID(context.Background(), realm, "unknown")
+	if !IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
 func TestClientsService_Get(t *testing.T) {
 	k := client(t)
 
@@ -155,3 +233,266 @@ func TestClientsService_CreateSecret(t *testing.T) {
 		t.Errorf("got: %t, want: %t", credential.Value == next.Value, credential.Value != next.Value)
 	}
 }
+
+func TestClientsService_Delete(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+
+	res, err := k.Clients.Delete(context.Background(), realm, clientID)
+	if err != nil {
+		t.Errorf("Clients.Delete returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	_, _, err = k.Clients.Get(context.Background(), realm, clientID)
+	if !IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestClientsService_GetServiceAccountUser(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+
+	user, res, err := k.Clients.GetServiceAccountUser(context.Background(), realm, clientID)
+	if err != nil {
+		t.Errorf("Clients.GetServiceAccountUser returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *user.Username != "service-account-client" {
+		t.Errorf("got: %s, want: %s", *user.Username, "service-account-client")
+	}
+}
+
+func TestClientsService_AddServiceAccountRealmRoles(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+	createRealmRole(t, k, realm, "role")
+
+	if _, err := k.Clients.AddServiceAccountRealmRoles(context.Background(), realm, clientID, "role"); err != nil {
+		t.Errorf("Clients.AddServiceAccountRealmRoles returned error: %v", err)
+	}
+
+	user, _, err := k.Clients.GetServiceAccountUser(context.Background(), realm, clientID)
+	if err != nil {
+		t.Errorf("Clients.GetServiceAccountUser returned error: %v", err)
+	}
+
+	roles, _, err := k.Users.ListRealmRoles(context.Background(), realm, *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListRealmRoles returned error: %v", err)
+	}
+
+	found := false
+	for _, role := range roles {
+		if *role.Name == "role" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got: %v, want: role", roles)
+	}
+}
+
+func TestClientsService_AddServiceAccountClientRoles_escapedName(t *testing.T) {
+	k := fake(t)
+	ctx := context.Background()
+
+	account, _, err := k.Clients.CreateAndGet(ctx, "master", &Client{ClientID: String("worker"), ServiceAccountsEnabled: Bool(true)})
+	if err != nil {
+		t.Fatalf("Clients.CreateAndGet returned error: %v", err)
+	}
+
+	api, _, err := k.Clients.CreateAndGet(ctx, "master", &Client{ClientID: String("api")})
+	if err != nil {
+		t.Fatalf("Clients.CreateAndGet returned error: %v", err)
+	}
+
+	name := "read all/reports"
+	if _, err := k.ClientRoles.Create(ctx, "master", *api.ID, &Role{Name: String(name)}); err != nil {
+		t.Fatalf("ClientRoles.Create returned error: %v", err)
+	}
+
+	if _, err := k.Clients.AddServiceAccountClientRoles(ctx, "master", *account.ID, *api.ID, name); err != nil {
+		t.Fatalf("Clients.AddServiceAccountClientRoles returned error: %v", err)
+	}
+
+	user, _, err := k.Clients.GetServiceAccountUser(ctx, "master", *account.ID)
+	if err != nil {
+		t.Fatalf("Clients.GetServiceAccountUser returned error: %v", err)
+	}
+
+	roles, _, err := k.Users.ListClientRoles(ctx, "master", *user.ID, *api.ID)
+	if err != nil {
+		t.Fatalf("Users.ListClientRoles returned error: %v", err)
+	}
+
+	if len(roles) != 1 || *roles[0].Name != name {
+		t.Errorf("got: %v, want: %s", roles, name)
+	}
+}
+
+func TestClientsService_AddServiceAccountRealmManagementRoles(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+
+	res, err := k.Clients.AddServiceAccountRealmManagementRoles(context.Background(), realm, clientID, "view-users", "manage-clients")
+	if err != nil {
+		t.Errorf("Clients.AddServiceAccountRealmManagementRoles returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestClientsService_ProtocolMappers(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+
+	res, err := k.Clients.CreateProtocolMapper(context.Background(), realm, clientID, NewAudienceMapper("audience", "client"))
+	if err != nil {
+		t.Errorf("Clients.CreateProtocolMapper returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusCreated)
+	}
+
+	mapperID, err := IDFromLocation(res)
+	if err != nil {
+		t.Errorf("IDFromLocation returned error: %v", err)
+	}
+
+	mapper, _, err := k.Clients.GetProtocolMapper(context.Background(), realm, clientID, mapperID)
+	if err != nil {
+		t.Errorf("Clients.GetProtocolMapper returned error: %v", err)
+	}
+
+	(*mapper.Config)["id.token.claim"] = "true"
+	if _, err := k.Clients.UpdateProtocolMapper(context.Background(), realm, clientID, mapper); err != nil {
+		t.Errorf("Clients.UpdateProtocolMapper returned error: %v", err)
+	}
+
+	mappers, _, err := k.Clients.ListProtocolMappers(context.Background(), realm, clientID)
+	if err != nil {
+		t.Errorf("Clients.ListProtocolMappers returned error: %v", err)
+	}
+
+	found := false
+	for _, m := range mappers {
+		if *m.Name == "audience" && (*m.Config)["id.token.claim"] == "true" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got: %v, want: updated audience mapper", mappers)
+	}
+
+	res, err = k.Clients.DeleteProtocolMapper(context.Background(), realm, clientID, mapperID)
+	if err != nil {
+		t.Errorf("Clients.DeleteProtocolMapper returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestClientsService_AddDefaultClientScope(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+	clientScopeID := createClientScope(t, k, realm, "scope")
+
+	res, err := k.Clients.AddDefaultClientScope(context.Background(), realm, clientID, clientScopeID)
+	if err != nil {
+		t.Errorf("Clients.AddDefaultClientScope returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	clientScopes, _, err := k.Clients.ListDefaultClientScopes(context.Background(), realm, clientID)
+	if err != nil {
+		t.Errorf("Clients.ListDefaultClientScopes returned error: %v", err)
+	}
+
+	found := false
+	for _, clientScope := range clientScopes {
+		if *clientScope.ID == clientScopeID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got: %v, want: %s", clientScopes, clientScopeID)
+	}
+
+	if _, err := k.Clients.RemoveDefaultClientScope(context.Background(), realm, clientID, clientScopeID); err != nil {
+		t.Errorf("Clients.RemoveDefaultClientScope returned error: %v", err)
+	}
+}
+
+func TestClientsService_AddOptionalClientScope(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientID := createClient(t, k, realm, "client")
+	clientScopeID := createClientScope(t, k, realm, "scope")
+
+	if _, err := k.Clients.AddOptionalClientScope(context.Background(), realm, clientID, clientScopeID); err != nil {
+		t.Errorf("Clients.AddOptionalClientScope returned error: %v", err)
+	}
+
+	clientScopes, _, err := k.Clients.ListOptionalClientScopes(context.Background(), realm, clientID)
+	if err != nil {
+		t.Errorf("Clients.ListOptionalClientScopes returned error: %v", err)
+	}
+
+	found := false
+	for _, clientScope := range clientScopes {
+		if *clientScope.ID == clientScopeID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got: %v, want: %s", clientScopes, clientScopeID)
+	}
+
+	if _, err := k.Clients.RemoveOptionalClientScope(context.Background(), realm, clientID, clientScopeID); err != nil {
+		t.Errorf("Clients.RemoveOptionalClientScope returned error: %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package keycloak
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultStreamInterval is the time between two polls of an event stream if
+// StreamOptions.Interval is not set.
+const DefaultStreamInterval = 5 * time.Second
+
+// Checkpoint is the position of an event stream. It is the time of the
+// newest delivered event together with the keys of all delivered events of
+// that millisecond, since Keycloak stores events with millisecond precision
+// only.
+type Checkpoint struct {
+	// Time is in milliseconds since the epoch.
+	Time int64 `json:"time"`
+	// Seen are the keys of the delivered events with exactly Time. Events
+	// without an ID may be identical, so a key appears once for every
+	// delivered event.
+	Seen []string `json:"seen,omitempty"`
+}
+
+// CheckpointStore persists the checkpoints of event streams, e.g. in a file
+// or a database, so a stream continues where it stopped after a restart.
+// The methods are never called concurrently for the same key.
+type CheckpointStore interface {
+	// Load returns the checkpoint for key or nil if there is none.
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+	// Save stores the checkpoint for key.
+	Save(ctx context.Context, key string, checkpoint *Checkpoint) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore which keeps the checkpoints in
+// memory. It is safe for concurrent use.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointStore returns an empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: map[string]Checkpoint{}}
+}
+
+// Load implements CheckpointStore.
+func (m *MemoryCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	checkpoint, ok := m.checkpoints[key]
+	if !ok {
+		return nil, nil
+	}
+	checkpoint.Seen = append([]string(nil), checkpoint.Seen...)
+	return &checkpoint, nil
+}
+
+// Save implements CheckpointStore.
+func (m *MemoryCheckpointStore) Save(ctx context.Context, key string, checkpoint *Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := *checkpoint
+	c.Seen = append([]string(nil), checkpoint.Seen...)
+	m.checkpoints[key] = c
+	return nil
+}
+
+// StreamOptions ...
+type StreamOptions struct {
+	// Interval is the time between two polls. It defaults to
+	// DefaultStreamInterval.
+	Interval time.Duration
+
+	// PageSize is the number of events fetched by the first request of a
+	// poll. It doubles until all events after the checkpoint are fetched.
+	// It defaults to DefaultPageSize.
+	PageSize int
+
+	// Checkpoints stores the position of the stream. By default the position
+	// is kept in memory and the stream starts over after a restart.
+	Checkpoints CheckpointStore
+
+	// Key identifies the stream in Checkpoints. It defaults to
+	// "events/<realm>" or "admin-events/<realm>".
+	Key string
+
+	// Since skips older events if there is no checkpoint yet. By default the
+	// stream starts with the oldest stored event.
+	Since time.Time
+
+	// OnError is called from the stream goroutine with every error of a poll
+	// or of saving a checkpoint. The stream keeps running and retries with
+	// the next poll.
+	OnError func(err error)
+}
+
+// Stream delivers new events of a realm as they occur. Keycloak has no push
+// API, so a Stream polls the events endpoint and remembers the newest
+// delivered event. Events are delivered at least once: an event may be
+// delivered again after a restart if its checkpoint could not be saved.
+type Stream[T any] struct {
+	events chan T
+	err    error
+}
+
+// Events returns the channel on which the events are delivered, oldest
+// first. It is closed when the stream stops.
+func (s *Stream[T]) Events() <-chan T {
+	return s.events
+}
+
+// Err returns the reason why the stream stopped, i.e. the error of the
+// context or of loading the checkpoint. It must only be called after the
+// Events channel is closed.
+func (s *Stream[T]) Err() error {
+	return s.err
+}
+
+// streamer polls a list endpoint of events which are ordered newest first.
+type streamer[T any] struct {
+	list func(ctx context.Context, page *Options) ([]T, *http.Response, error)
+	time func(event T) int64
+	opts StreamOptions
+}
+
+// Stream polls the user events of the realm which match query. The paging
+// options of query are ignored. The stream stops when ctx is canceled.
+func (s *EventsService) Stream(ctx context.Context, realm string, query *EventQuery, opts *StreamOptions) *Stream[*Event] {
+	var filter EventQuery
+	if query != nil {
+		filter = *query
+	}
+	st := &streamer[*Event]{
+		list: func(ctx context.Context, page *Options) ([]*Event, *http.Response, error) {
+			q := filter
+			q.Options = *page
+			return s.List(ctx, realm, &q)
+		},
+		time: func(e *Event) int64 {
+			if e.Time == nil {
+				return 0
+			}
+			return *e.Time
+		},
+	}
+	return st.start(ctx, "events/"+realm, opts)
+}
+
+// StreamAdmin polls the admin events of the realm which match query. The
+// paging options of query are ignored. The stream stops when ctx is
+// canceled.
+func (s *EventsService) StreamAdmin(ctx context.Context, realm string, query *AdminEventQuery, opts *StreamOptions) *Stream[*AdminEvent] {
+	var filter AdminEventQuery
+	if query != nil {
+		filter = *query
+	}
+	st := &streamer[*AdminEvent]{
+		list: func(ctx context.Context, page *Options) ([]*AdminEvent, *http.Response, error) {
+			q := filter
+			q.Options = *page
+			return s.ListAdmin(ctx, realm, &q)
+		},
+		time: func(e *AdminEvent) int64 {
+			if e.Time == nil {
+				return 0
+			}
+			return *e.Time
+		},
+	}
+	return st.start(ctx, "admin-events/"+realm, opts)
+}
+
+func (st *streamer[T]) start(ctx context.Context, key string, opts *StreamOptions) *Stream[T] {
+	if opts != nil {
+		st.opts = *opts
+	}
+	if st.opts.Interval <= 0 {
+		st.opts.Interval = DefaultStreamInterval
+	}
+	if st.opts.PageSize <= 0 {
+		st.opts.PageSize = DefaultPageSize
+	}
+	if st.opts.Checkpoints == nil {
+		st.opts.Checkpoints = NewMemoryCheckpointStore()
+	}
+	if st.opts.Key == "" {
+		st.opts.Key = key
+	}
+
+	stream := &Stream[T]{events: make(chan T)}
+	go func() {
+		defer close(stream.events)
+		stream.err = st.run(ctx, stream.events)
+	}()
+	return stream
+}
+
+// run polls until ctx is canceled.
+func (st *streamer[T]) run(ctx context.Context, events chan<- T) error {
+	checkpoint, err := st.opts.Checkpoints.Load(ctx, st.opts.Key)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		checkpoint = &Checkpoint{}
+		if !st.opts.Since.IsZero() {
+			checkpoint.Time = st.opts.Since.UnixNano() / int64(time.Millisecond)
+		}
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := st.poll(ctx, checkpoint, events); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			st.report(err)
+		}
+		timer.Reset(st.opts.Interval)
+	}
+}
+
+// poll delivers all events after checkpoint and advances it.
+func (st *streamer[T]) poll(ctx context.Context, checkpoint *Checkpoint, events chan<- T) error {
+	// the events are ordered newest first. Paging with an offset would show
+	// an event twice if new events arrive meanwhile, which cannot be told
+	// apart from two identical events. Hence the events after the checkpoint
+	// are read with a single request whose size doubles until it reaches the
+	// checkpoint.
+	page := Options{Max: st.opts.PageSize}
+	var list []T
+	for {
+		var err error
+		list, _, err = st.list(ctx, &page)
+		if err != nil {
+			return err
+		}
+		if len(list) < page.Max || st.time(list[len(list)-1]) < checkpoint.Time {
+			break
+		}
+		page.Max *= 2
+	}
+
+	// the events of the checkpoint millisecond which were already delivered
+	delivered := make(map[string]int, len(checkpoint.Seen))
+	for _, key := range checkpoint.Seen {
+		delivered[key]++
+	}
+
+	type item struct {
+		event T
+		key   string
+	}
+	var items []item
+	for _, event := range list {
+		t := st.time(event)
+		if t < checkpoint.Time {
+			break
+		}
+		key, err := eventKey(event)
+		if err != nil {
+			return err
+		}
+		items = append(items, item{event, key})
+	}
+
+	// oldest first, keeping the order of events of the same millisecond
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return st.time(items[i].event) < st.time(items[j].event)
+	})
+
+	for _, it := range items {
+		t := st.time(it.event)
+		if t == checkpoint.Time && delivered[it.key] > 0 {
+			// identical events are interchangeable, so skip as many as were
+			// delivered
+			delivered[it.key]--
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case events <- it.event:
+		}
+
+		if t > checkpoint.Time {
+			checkpoint.Time = t
+			checkpoint.Seen = []string{it.key}
+			delivered = map[string]int{}
+		} else {
+			checkpoint.Seen = append(checkpoint.Seen, it.key)
+		}
+		if err := st.opts.Checkpoints.Save(ctx, st.opts.Key, checkpoint); err != nil {
+			st.report(err)
+		}
+	}
+	return nil
+}
+
+func (st *streamer[T]) report(err error) {
+	if st.opts.OnError != nil {
+		st.opts.OnError(err)
+	}
+}
+
+// eventKey identifies an event by the hash of its JSON representation.
+func eventKey(event interface{}) (string, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16]), nil
+}
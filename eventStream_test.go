@@ -0,0 +1,250 @@
+package keycloak
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zemirco/keycloak/v2/keycloaktest"
+)
+
+// receive returns the next n events of the stream.
+func receive[T any](t *testing.T, stream *Stream[T], n int) []T {
+	t.Helper()
+
+	var events []T
+	for len(events) < n {
+		select {
+		case e, ok := <-stream.Events():
+			if !ok {
+				t.Fatalf("stream stopped: %v", stream.Err())
+			}
+			events = append(events, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got: %d events, want: %d", len(events), n)
+		}
+	}
+	return events
+}
+
+// addEvents adds login events of the given types and times to the realm
+// "master".
+func addEvents(t *testing.T, server *keycloaktest.Server, types []string, times []int64) {
+	t.Helper()
+
+	for i := range types {
+		event := map[string]interface{}{"type": types[i], "time": times[i], "clientId": "account"}
+		if err := server.AddEvent("master", event); err != nil {
+			t.Fatalf("AddEvent returned error: %v", err)
+		}
+	}
+}
+
+func TestEventsService_Stream(t *testing.T) {
+	server := keycloaktest.NewServer()
+	t.Cleanup(server.Close)
+
+	k, err := NewKeycloak(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addEvents(t, server, []string{"LOGIN", "LOGOUT", "LOGIN_ERROR"}, []int64{1000, 2000, 2000})
+
+	checkpoints := NewMemoryCheckpointStore()
+	opts := &StreamOptions{
+		Interval:    10 * time.Millisecond,
+		PageSize:    2,
+		Checkpoints: checkpoints,
+		OnError: func(err error) {
+			t.Errorf("stream returned error: %v", err)
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := k.Events.Stream(ctx, "master", nil, opts)
+
+	events := receive(t, stream, 3)
+	for i, want := range []string{"LOGIN", "LOGOUT", "LOGIN_ERROR"} {
+		if *events[i].Type != want {
+			t.Errorf("got: %s, want: %s", *events[i].Type, want)
+		}
+	}
+
+	// an event of the same millisecond as the newest delivered one
+	addEvents(t, server, []string{"CODE_TO_TOKEN", "REFRESH_TOKEN"}, []int64{2000, 3000})
+
+	events = receive(t, stream, 2)
+	if *events[0].Type != "CODE_TO_TOKEN" || *events[1].Type != "REFRESH_TOKEN" {
+		t.Errorf("got: %s %s, want: CODE_TO_TOKEN REFRESH_TOKEN", *events[0].Type, *events[1].Type)
+	}
+
+	cancel()
+	for range stream.Events() {
+		t.Errorf("got: event, want: none")
+	}
+	if !errors.Is(stream.Err(), context.Canceled) {
+		t.Errorf("got: %v, want: %v", stream.Err(), context.Canceled)
+	}
+
+	checkpoint, err := checkpoints.Load(context.Background(), "events/master")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if checkpoint.Time != 3000 || len(checkpoint.Seen) != 1 {
+		t.Errorf("got: %d %v, want: 3000 and one key", checkpoint.Time, checkpoint.Seen)
+	}
+
+	// a restarted stream continues at the checkpoint
+	addEvents(t, server, []string{"LOGOUT"}, []int64{4000})
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	stream = k.Events.Stream(ctx, "master", nil, opts)
+
+	events = receive(t, stream, 1)
+	if *events[0].Time != 4000 {
+		t.Errorf("got: %d, want: %d", *events[0].Time, 4000)
+	}
+}
+
+func TestEventsService_Stream_identicalEvents(t *testing.T) {
+	server := keycloaktest.NewServer()
+	t.Cleanup(server.Close)
+
+	k, err := NewKeycloak(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a brute force burst without event IDs
+	addEvents(t, server, []string{"LOGIN_ERROR", "LOGIN_ERROR"}, []int64{1000, 1000})
+
+	checkpoints := NewMemoryCheckpointStore()
+	opts := &StreamOptions{
+		Interval:    10 * time.Millisecond,
+		PageSize:    1,
+		Checkpoints: checkpoints,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := k.Events.Stream(ctx, "master", nil, opts)
+
+	events := receive(t, stream, 2)
+	for _, event := range events {
+		if event.ID != nil || *event.Time != 1000 {
+			t.Errorf("got: %v %d, want: no id and time 1000", event.ID, *event.Time)
+		}
+	}
+
+	// another identical event of the same millisecond
+	addEvents(t, server, []string{"LOGIN_ERROR"}, []int64{1000})
+	receive(t, stream, 1)
+
+	cancel()
+	for range stream.Events() {
+		t.Errorf("got: event, want: none")
+	}
+
+	checkpoint, err := checkpoints.Load(context.Background(), "events/master")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(checkpoint.Seen) != 3 {
+		t.Errorf("got: %d, want: %d", len(checkpoint.Seen), 3)
+	}
+
+	// a restarted stream delivers only the new identical event
+	addEvents(t, server, []string{"LOGIN_ERROR"}, []int64{1000})
+
+	ctx, cancel = context.WithCancel(context.Background())
+	stream = k.Events.Stream(ctx, "master", nil, opts)
+	receive(t, stream, 1)
+
+	select {
+	case event := <-stream.Events():
+		t.Errorf("got: %v, want: no event", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+	cancel()
+	for range stream.Events() {
+	}
+}
+
+func TestEventsService_Stream_since(t *testing.T) {
+	server := keycloaktest.NewServer()
+	t.Cleanup(server.Close)
+
+	k, err := NewKeycloak(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addEvents(t, server, []string{"LOGIN", "LOGIN_ERROR", "LOGIN"}, []int64{1000, 2000, 3000})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := k.Events.Stream(ctx, "master", &EventQuery{Types: []string{"LOGIN"}}, &StreamOptions{
+		Interval: 10 * time.Millisecond,
+		Since:    time.Unix(2, 0),
+	})
+
+	events := receive(t, stream, 1)
+	if *events[0].Time != 3000 {
+		t.Errorf("got: %d, want: %d", *events[0].Time, 3000)
+	}
+}
+
+func TestEventsService_StreamAdmin(t *testing.T) {
+	k := fake(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := k.Events.UpdateConfig(ctx, "master", &RealmEventsConfig{AdminEventsEnabled: Bool(true)}); err != nil {
+		t.Fatalf("Events.UpdateConfig returned error: %v", err)
+	}
+
+	stream := k.Events.StreamAdmin(ctx, "master", &AdminEventQuery{ResourceTypes: []string{"USER"}}, &StreamOptions{
+		Interval: 10 * time.Millisecond,
+	})
+
+	for _, username := range []string{"first", "second"} {
+		if _, err := k.Users.Create(ctx, "master", &User{Username: String(username)}); err != nil {
+			t.Fatalf("Users.Create returned error: %v", err)
+		}
+	}
+
+	events := receive(t, stream, 2)
+	for _, event := range events {
+		if *event.OperationType != "CREATE" {
+			t.Errorf("got: %s, want: %s", *event.OperationType, "CREATE")
+		}
+	}
+}
+
+type failingCheckpointStore struct{}
+
+func (failingCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (failingCheckpointStore) Save(ctx context.Context, key string, checkpoint *Checkpoint) error {
+	return errors.New("unavailable")
+}
+
+func TestEventsService_Stream_loadError(t *testing.T) {
+	k := fake(t)
+
+	stream := k.Events.Stream(context.Background(), "master", nil, &StreamOptions{
+		Checkpoints: failingCheckpointStore{},
+	})
+
+	for range stream.Events() {
+		t.Errorf("got: event, want: none")
+	}
+	if stream.Err() == nil {
+		t.Errorf("got: nil, want: error")
+	}
+}
@@ -0,0 +1,228 @@
+package keycloak
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Event representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/EventRepresentation.java
+type Event struct {
+	ID *string `json:"id,omitempty"`
+	// Time is the time of the event in milliseconds since the epoch.
+	Time      *int64             `json:"time,omitempty"`
+	Type      *string            `json:"type,omitempty"`
+	RealmID   *string            `json:"realmId,omitempty"`
+	ClientID  *string            `json:"clientId,omitempty"`
+	UserID    *string            `json:"userId,omitempty"`
+	SessionID *string            `json:"sessionId,omitempty"`
+	IPAddress *string            `json:"ipAddress,omitempty"`
+	Error     *string            `json:"error,omitempty"`
+	Details   *map[string]string `json:"details,omitempty"`
+}
+
+// AdminEvent representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/AdminEventRepresentation.java
+type AdminEvent struct {
+	ID *string `json:"id,omitempty"`
+	// Time is the time of the event in milliseconds since the epoch.
+	Time        *int64       `json:"time,omitempty"`
+	RealmID     *string      `json:"realmId,omitempty"`
+	AuthDetails *AuthDetails `json:"authDetails,omitempty"`
+	// OperationType is one of "CREATE", "UPDATE", "DELETE" or "ACTION".
+	OperationType *string `json:"operationType,omitempty"`
+	ResourceType  *string `json:"resourceType,omitempty"`
+	ResourcePath  *string `json:"resourcePath,omitempty"`
+	// Representation is the JSON encoded resource. It is only set if the
+	// realm has AdminEventsDetailsEnabled.
+	Representation *string            `json:"representation,omitempty"`
+	Error          *string            `json:"error,omitempty"`
+	Details        *map[string]string `json:"details,omitempty"`
+}
+
+// AuthDetails describes who caused an admin event.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/AuthDetailsRepresentation.java
+type AuthDetails struct {
+	RealmID   *string `json:"realmId,omitempty"`
+	ClientID  *string `json:"clientId,omitempty"`
+	UserID    *string `json:"userId,omitempty"`
+	IPAddress *string `json:"ipAddress,omitempty"`
+}
+
+// RealmEventsConfig representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/RealmEventsConfigRepresentation.java
+type RealmEventsConfig struct {
+	EventsEnabled *bool `json:"eventsEnabled,omitempty"`
+	// EventsExpiration is the number of seconds after which events are
+	// removed.
+	EventsExpiration          *int64   `json:"eventsExpiration,omitempty"`
+	EventsListeners           []string `json:"eventsListeners,omitempty"`
+	EnabledEventTypes         []string `json:"enabledEventTypes,omitempty"`
+	AdminEventsEnabled        *bool    `json:"adminEventsEnabled,omitempty"`
+	AdminEventsDetailsEnabled *bool    `json:"adminEventsDetailsEnabled,omitempty"`
+}
+
+// EventQuery specifies the optional parameters to the EventsService.List
+// method.
+type EventQuery struct {
+	// Types are event types like "LOGIN" or "LOGIN_ERROR".
+	Types     []string `url:"type,omitempty"`
+	Client    string   `url:"client,omitempty"`
+	User      string   `url:"user,omitempty"`
+	IPAddress string   `url:"ipAddress,omitempty"`
+	// DateFrom and DateTo limit the events to the given days in the format
+	// "2006-01-02". Both days are included.
+	DateFrom string `url:"dateFrom,omitempty"`
+	DateTo   string `url:"dateTo,omitempty"`
+	Options
+}
+
+// AdminEventQuery specifies the optional parameters to the
+// EventsService.ListAdmin method.
+type AdminEventQuery struct {
+	// OperationTypes are "CREATE", "UPDATE", "DELETE" or "ACTION".
+	OperationTypes []string `url:"operationTypes,omitempty"`
+	// ResourceTypes are resource types like "USER" or "CLIENT".
+	ResourceTypes []string `url:"resourceTypes,omitempty"`
+	// ResourcePath supports "*" as wildcard, e.g. "users/*".
+	ResourcePath  string `url:"resourcePath,omitempty"`
+	AuthRealm     string `url:"authRealm,omitempty"`
+	AuthClient    string `url:"authClient,omitempty"`
+	AuthUser      string `url:"authUser,omitempty"`
+	AuthIPAddress string `url:"authIpAddress,omitempty"`
+	// DateFrom and DateTo limit the events to the given days in the format
+	// "2006-01-02". Both days are included.
+	DateFrom string `url:"dateFrom,omitempty"`
+	DateTo   string `url:"dateTo,omitempty"`
+	Options
+}
+
+// EventsService handles communication with the event related methods of the Keycloak API.
+type EventsService service
+
+// List returns the user events of the realm, newest first. Events are only
+// stored if EventsEnabled is set for the realm.
+func (s *EventsService) List(ctx context.Context, realm string, opts *EventQuery) ([]*Event, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/events", realm)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []*Event
+	res, err := s.keycloak.Do(ctx, req, &events)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, res, nil
+}
+
+// Pager returns a Pager over the user events of the realm.
+func (s *EventsService) Pager(realm string, opts *EventQuery) *Pager[*Event] {
+	var filter EventQuery
+	if opts != nil {
+		filter = *opts
+	}
+	return NewPager(&filter.Options, func(ctx context.Context, page *Options) ([]*Event, *http.Response, error) {
+		o := filter
+		o.Options = *page
+		return s.List(ctx, realm, &o)
+	})
+}
+
+// ListAdmin returns the admin events of the realm, newest first. Admin events
+// are only stored if AdminEventsEnabled is set for the realm.
+func (s *EventsService) ListAdmin(ctx context.Context, realm string, opts *AdminEventQuery) ([]*AdminEvent, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/admin-events", realm)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []*AdminEvent
+	res, err := s.keycloak.Do(ctx, req, &events)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, res, nil
+}
+
+// AdminPager returns a Pager over the admin events of the realm.
+func (s *EventsService) AdminPager(realm string, opts *AdminEventQuery) *Pager[*AdminEvent] {
+	var filter AdminEventQuery
+	if opts != nil {
+		filter = *opts
+	}
+	return NewPager(&filter.Options, func(ctx context.Context, page *Options) ([]*AdminEvent, *http.Response, error) {
+		o := filter
+		o.Options = *page
+		return s.ListAdmin(ctx, realm, &o)
+	})
+}
+
+// ClearEvents deletes all user events of the realm.
+func (s *EventsService) ClearEvents(ctx context.Context, realm string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/events", realm)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ClearAdminEvents deletes all admin events of the realm.
+func (s *EventsService) ClearAdminEvents(ctx context.Context, realm string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/admin-events", realm)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// GetConfig returns the events configuration of the realm.
+func (s *EventsService) GetConfig(ctx context.Context, realm string) (*RealmEventsConfig, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/events/config", realm)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var config RealmEventsConfig
+	res, err := s.keycloak.Do(ctx, req, &config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &config, res, nil
+}
+
+// UpdateConfig updates the events configuration of the realm.
+func (s *EventsService) UpdateConfig(ctx context.Context, realm string, config *RealmEventsConfig) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/events/config", realm)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
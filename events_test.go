@@ -0,0 +1,163 @@
+package keycloak
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func enableEvents(t *testing.T, k *Keycloak, realm string) {
+	t.Helper()
+
+	config := &RealmEventsConfig{
+		EventsEnabled:             Bool(true),
+		AdminEventsEnabled:        Bool(true),
+		AdminEventsDetailsEnabled: Bool(true),
+	}
+
+	if _, err := k.Events.UpdateConfig(context.Background(), realm, config); err != nil {
+		t.Fatalf("Events.UpdateConfig returned error: %v", err)
+	}
+}
+
+func TestEventsService_Config(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	ctx := context.Background()
+
+	config := &RealmEventsConfig{
+		EventsEnabled:     Bool(true),
+		EventsExpiration:  Int64(3600),
+		EnabledEventTypes: []string{"LOGIN", "LOGIN_ERROR"},
+	}
+
+	res, err := k.Events.UpdateConfig(ctx, realm, config)
+	if err != nil {
+		t.Fatalf("Events.UpdateConfig returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	config, _, err = k.Events.GetConfig(ctx, realm)
+	if err != nil {
+		t.Fatalf("Events.GetConfig returned error: %v", err)
+	}
+
+	if !*config.EventsEnabled {
+		t.Errorf("got: %t, want: %t", *config.EventsEnabled, true)
+	}
+
+	if *config.EventsExpiration != 3600 {
+		t.Errorf("got: %d, want: %d", *config.EventsExpiration, 3600)
+	}
+
+	if len(config.EnabledEventTypes) != 2 {
+		t.Errorf("got: %d, want: %d", len(config.EnabledEventTypes), 2)
+	}
+}
+
+func TestEventsService_List(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	enableEvents(t, k, realm)
+
+	ctx := context.Background()
+
+	userID := createUser(t, k, realm, "user")
+	login(t, k, realm, userID, "user")
+
+	if _, err := NewWithPassword(ctx, "http://localhost:8080/", realm, "admin-cli", "user", "wrong"); err == nil {
+		t.Errorf("got: nil, want: error")
+	}
+
+	events, res, err := k.Events.List(ctx, realm, &EventQuery{
+		Types: []string{"LOGIN_ERROR"},
+		User:  userID,
+	})
+	if err != nil {
+		t.Fatalf("Events.List returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("got: %d, want: %d", len(events), 1)
+	}
+
+	if *events[0].ClientID != "admin-cli" {
+		t.Errorf("got: %s, want: %s", *events[0].ClientID, "admin-cli")
+	}
+
+	res, err = k.Events.ClearEvents(ctx, realm)
+	if err != nil {
+		t.Errorf("Events.ClearEvents returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	events, _, err = k.Events.List(ctx, realm, nil)
+	if err != nil {
+		t.Fatalf("Events.List returned error: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Errorf("got: %d, want: %d", len(events), 0)
+	}
+}
+
+func TestEventsService_ListAdmin(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	enableEvents(t, k, realm)
+
+	ctx := context.Background()
+
+	userID := createUser(t, k, realm, "user")
+
+	events, res, err := k.Events.ListAdmin(ctx, realm, &AdminEventQuery{
+		OperationTypes: []string{"CREATE"},
+		ResourceTypes:  []string{"USER"},
+		ResourcePath:   "users/*",
+	})
+	if err != nil {
+		t.Fatalf("Events.ListAdmin returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("got: %d, want: %d", len(events), 1)
+	}
+
+	if *events[0].ResourcePath != "users/"+userID {
+		t.Errorf("got: %s, want: %s", *events[0].ResourcePath, "users/"+userID)
+	}
+
+	if events[0].Representation == nil {
+		t.Errorf("got: nil, want: representation")
+	}
+
+	res, err = k.Events.ClearAdminEvents(ctx, realm)
+	if err != nil {
+		t.Errorf("Events.ClearAdminEvents returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+}
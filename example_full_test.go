@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/zemirco/keycloak/v2"
 	"golang.org/x/oauth2"
@@ -75,8 +74,10 @@ func Example_full() {
 		panic(err)
 	}
 
-	parts := strings.Split(res.Header.Get("Location"), "/")
-	id := parts[len(parts)-1]
+	id, err := keycloak.IDFromLocation(res)
+	if err != nil {
+		panic(err)
+	}
 
 	// get the client to have all properties
 	client, res, err = kc.Clients.Get(ctx, realm, id)
@@ -101,14 +102,11 @@ func Example_full() {
 		Username: keycloak.String("user_a"),
 	}
 
-	res, err = kc.Users.Create(ctx, realm, userA)
+	userA, res, err = kc.Users.CreateAndGet(ctx, realm, userA)
 	if err != nil {
 		panic(err)
 	}
 
-	parts = strings.Split(res.Header.Get("Location"), "/")
-	userA.ID = &parts[len(parts)-1]
-
 	// set password for user_a
 	res, err = kc.Users.ResetPassword(ctx, realm, *userA.ID, &keycloak.Credential{
 		Type:      keycloak.String("password"),
@@ -125,14 +123,11 @@ func Example_full() {
 		Username: keycloak.String("user_b"),
 	}
 
-	res, err = kc.Users.Create(ctx, realm, userB)
+	userB, res, err = kc.Users.CreateAndGet(ctx, realm, userB)
 	if err != nil {
 		panic(err)
 	}
 
-	parts = strings.Split(res.Header.Get("Location"), "/")
-	userB.ID = &parts[len(parts)-1]
-
 	// set password for user_b
 	res, err = kc.Users.ResetPassword(ctx, realm, *userB.ID, &keycloak.Credential{
 		Type:      keycloak.String("password"),
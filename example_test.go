@@ -3,6 +3,7 @@ package keycloak_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/zemirco/keycloak/v2"
 	"golang.org/x/oauth2"
@@ -69,6 +70,31 @@ func ExampleNewKeycloak_user() {
 	fmt.Println(kc)
 }
 
+func ExampleNewWithPassword() {
+	ctx := context.Background()
+
+	// this should be your KEYCLOAK_USER and your KEYCLOAK_PASSWORD
+	kc, err := keycloak.NewWithPassword(ctx, "http://localhost:8080/", "master", "admin-cli", "admin", "admin")
+	if err != nil {
+		panic(err)
+	}
+
+	// tokens are refreshed automatically and the instance logs in again when the session expires
+	fmt.Println(kc)
+}
+
+func ExampleNewWithClientCredentials() {
+	ctx := context.Background()
+
+	// the client needs "ServiceAccountsEnabled" and the service account user the required roles
+	kc, err := keycloak.NewWithClientCredentials(ctx, "http://localhost:8080/", "myrealm", "myclient", "ddafbeba-1402-4969-bbbb-475d657fa9d5")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(kc)
+}
+
 func ExampleRealmsService_Create() {
 	kc, err := keycloak.NewKeycloak(nil, "http://localhost:8080/")
 	if err != nil {
@@ -242,3 +268,69 @@ func ExampleGroupsService_Create() {
 
 	fmt.Println(res)
 }
+
+func ExampleUsersService_Import() {
+	kc, err := keycloak.NewKeycloak(nil, "http://localhost:8080/")
+	if err != nil {
+		panic(err)
+	}
+
+	// the password hash, salt and iterations come from the legacy system
+	users := []*keycloak.User{
+		{
+			Enabled:  keycloak.Bool(true),
+			Username: keycloak.String("john"),
+			Credentials: []*keycloak.Credential{
+				keycloak.NewHashedPasswordCredential("pbkdf2-sha256", 27500, []byte("hash"), []byte("salt")),
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	report, err := kc.Users.Import(ctx, "myrealm", users, &keycloak.ImportOptions{
+		Concurrency:  8,
+		SkipExisting: true,
+		Progress: func(report keycloak.ImportReport) {
+			fmt.Printf("%d of %d\n", report.Next, report.Total)
+		},
+	})
+	if err != nil {
+		// resume later with ImportOptions.Start set to report.Next
+		panic(err)
+	}
+
+	for _, err := range report.Errors {
+		fmt.Println(err)
+	}
+}
+
+func ExampleEventsService_Stream() {
+	kc, err := keycloak.NewKeycloak(nil, "http://localhost:8080/")
+	if err != nil {
+		panic(err)
+	}
+
+	// cancel the context to stop the stream
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// use a persistent CheckpointStore to continue after a restart
+	stream := kc.Events.Stream(ctx, "myrealm", &keycloak.EventQuery{
+		Types: []string{"LOGIN", "LOGIN_ERROR"},
+	}, &keycloak.StreamOptions{
+		Interval:    10 * time.Second,
+		Checkpoints: keycloak.NewMemoryCheckpointStore(),
+		OnError: func(err error) {
+			fmt.Println(err)
+		},
+	})
+
+	for event := range stream.Events() {
+		fmt.Println(*event.Type, *event.UserID)
+	}
+
+	if err := stream.Err(); err != nil && err != context.Canceled {
+		panic(err)
+	}
+}
@@ -32,9 +32,29 @@ func (s *GroupsService) Create(ctx context.Context, realm string, group *Group)
 	return s.keycloak.Do(ctx, req, nil)
 }
 
+// CreateAndGet creates a new group and returns the created group with all its properties.
+func (s *GroupsService) CreateAndGet(ctx context.Context, realm string, group *Group) (*Group, *http.Response, error) {
+	res, err := s.Create(ctx, realm, group)
+	if err != nil {
+		return nil, res, err
+	}
+
+	id, err := IDFromLocation(res)
+	if err != nil {
+		return nil, res, err
+	}
+
+	return s.Get(ctx, realm, id)
+}
+
 // List groups.
-func (s *GroupsService) List(ctx context.Context, realm string) ([]*Group, *http.Response, error) {
+func (s *GroupsService) List(ctx context.Context, realm string, opts *Options) ([]*Group, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/groups", realm)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -49,6 +69,13 @@ func (s *GroupsService) List(ctx context.Context, realm string) ([]*Group, *http
 	return groups, res, nil
 }
 
+// Pager returns a Pager which iterates over all groups.
+func (s *GroupsService) Pager(realm string, opts *Options) *Pager[*Group] {
+	return NewPager(opts, func(ctx context.Context, opts *Options) ([]*Group, *http.Response, error) {
+		return s.List(ctx, realm, opts)
+	})
+}
+
 // Get group.
 func (s *GroupsService) Get(ctx context.Context, realm, groupID string) (*Group, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/groups/%s", realm, groupID)
@@ -98,3 +125,149 @@ func (s *GroupsService) RemoveRealmRoles(ctx context.Context, realm, groupID str
 
 	return s.keycloak.Do(ctx, req, nil)
 }
+
+// GetRoleMappings returns the realm and client roles assigned to group.
+func (s *GroupsService) GetRoleMappings(ctx context.Context, realm, groupID string) (*Mappings, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings", realm, groupID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mappings Mappings
+	res, err := s.keycloak.Do(ctx, req, &mappings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mappings, res, nil
+}
+
+// ListRealmRoles returns a list of realm roles assigned to group.
+func (s *GroupsService) ListRealmRoles(ctx context.Context, realm, groupID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/realm", realm, groupID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListCompositeRealmRoles returns the effective realm roles of group, i.e.
+// the assigned roles including the roles of all composites.
+func (s *GroupsService) ListCompositeRealmRoles(ctx context.Context, realm, groupID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/realm/composite", realm, groupID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListAvailableRealmRoles returns the realm roles which can still be assigned to
+// group.
+func (s *GroupsService) ListAvailableRealmRoles(ctx context.Context, realm, groupID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/realm/available", realm, groupID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// AddClientRoles adds client roles to group.
+func (s *GroupsService) AddClientRoles(ctx context.Context, realm, groupID, clientID string, roles []*Role) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/clients/%s", realm, groupID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// RemoveClientRoles removes assigned client roles from group.
+func (s *GroupsService) RemoveClientRoles(ctx context.Context, realm, groupID, clientID string, roles []*Role) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/clients/%s", realm, groupID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListClientRoles returns a list of roles of the client with clientID assigned
+// to group.
+func (s *GroupsService) ListClientRoles(ctx context.Context, realm, groupID, clientID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/clients/%s", realm, groupID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListCompositeClientRoles returns the effective roles of the client with
+// clientID of group including the roles of all composites.
+func (s *GroupsService) ListCompositeClientRoles(ctx context.Context, realm, groupID, clientID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/clients/%s/composite", realm, groupID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListAvailableClientRoles returns the roles of the client with clientID which
+// can still be assigned to group.
+func (s *GroupsService) ListAvailableClientRoles(ctx context.Context, realm, groupID, clientID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/groups/%s/role-mappings/clients/%s/available", realm, groupID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
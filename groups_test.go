@@ -3,7 +3,6 @@ package keycloak
 import (
 	"context"
 	"net/http"
-	"strings"
 	"testing"
 )
 
@@ -20,8 +19,10 @@ func createGroup(t *testing.T, k *Keycloak, realm string, groupName string) stri
 		t.Errorf("Groups.Create returned error: %v", err)
 	}
 
-	parts := strings.Split(res.Header.Get("Location"), "/")
-	groupID := parts[len(parts)-1]
+	groupID, err := IDFromLocation(res)
+	if err != nil {
+		t.Errorf("IDFromLocation returned error: %v", err)
+	}
 	return groupID
 }
 
@@ -47,6 +48,30 @@ func TestGroupsService_Create(t *testing.T) {
 	}
 }
 
+func TestGroupsService_CreateAndGet(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	group := &Group{
+		Name: String("mygroup"),
+	}
+
+	group, res, err := k.Groups.CreateAndGet(context.Background(), realm, group)
+	if err != nil {
+		t.Errorf("Groups.CreateAndGet returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *group.Path != "/mygroup" {
+		t.Errorf("got: %s, want: %s", *group.Path, "/mygroup")
+	}
+}
+
 func TestGroupsService_List(t *testing.T) {
 	k := client(t)
 
@@ -56,7 +81,7 @@ func TestGroupsService_List(t *testing.T) {
 	createGroup(t, k, realm, "group_a")
 	createGroup(t, k, realm, "group_b")
 
-	groups, res, err := k.Groups.List(context.Background(), realm)
+	groups, res, err := k.Groups.List(context.Background(), realm, nil)
 	if err != nil {
 		t.Errorf("Groups.List returned error: %v", err)
 	}
@@ -182,3 +207,153 @@ func TestGroupsService_RemoveRealmRoles(t *testing.T) {
 		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
 	}
 }
+
+func TestGroupsService_GetRoleMappings(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createRealmRole(t, k, realm, "role")
+	clientID := createClient(t, k, realm, "client")
+	createClientRole(t, k, realm, clientID, "client-role")
+	groupID := createGroup(t, k, realm, "group")
+
+	ctx := context.Background()
+
+	role, _, err := k.RealmRoles.GetByName(ctx, realm, "role")
+	if err != nil {
+		t.Fatalf("RealmRoles.GetByName returned error: %v", err)
+	}
+	if _, err := k.Groups.AddRealmRoles(ctx, realm, groupID, []*Role{role}); err != nil {
+		t.Errorf("Groups.AddRealmRoles returned error: %v", err)
+	}
+
+	clientRole, _, err := k.ClientRoles.Get(ctx, realm, clientID, "client-role")
+	if err != nil {
+		t.Fatalf("ClientRoles.Get returned error: %v", err)
+	}
+	res, err := k.Groups.AddClientRoles(ctx, realm, groupID, clientID, []*Role{clientRole})
+	if err != nil {
+		t.Errorf("Groups.AddClientRoles returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	mappings, _, err := k.Groups.GetRoleMappings(ctx, realm, groupID)
+	if err != nil {
+		t.Fatalf("Groups.GetRoleMappings returned error: %v", err)
+	}
+
+	if len(mappings.RealmMappings) != 1 {
+		t.Errorf("got: %d, want: %d", len(mappings.RealmMappings), 1)
+	}
+
+	if !hasRole(mappings.ClientMappings["client"].Mappings, "client-role") {
+		t.Errorf("got: %v, want: client-role", mappings.ClientMappings)
+	}
+}
+
+func TestGroupsService_ListRealmRoles(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createRealmRole(t, k, realm, "role1")
+	createRealmRole(t, k, realm, "role2")
+	groupID := createGroup(t, k, realm, "group")
+
+	ctx := context.Background()
+
+	role, _, err := k.RealmRoles.GetByName(ctx, realm, "role1")
+	if err != nil {
+		t.Fatalf("RealmRoles.GetByName returned error: %v", err)
+	}
+	if _, err := k.Groups.AddRealmRoles(ctx, realm, groupID, []*Role{role}); err != nil {
+		t.Errorf("Groups.AddRealmRoles returned error: %v", err)
+	}
+
+	roles, res, err := k.Groups.ListRealmRoles(ctx, realm, groupID)
+	if err != nil {
+		t.Errorf("Groups.ListRealmRoles returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(roles) != 1 {
+		t.Errorf("got: %d, want: %d", len(roles), 1)
+	}
+
+	roles, _, err = k.Groups.ListCompositeRealmRoles(ctx, realm, groupID)
+	if err != nil {
+		t.Errorf("Groups.ListCompositeRealmRoles returned error: %v", err)
+	}
+
+	if !hasRole(roles, "role1") || hasRole(roles, "role2") {
+		t.Errorf("got: %v, want: role1", roles)
+	}
+
+	roles, _, err = k.Groups.ListAvailableRealmRoles(ctx, realm, groupID)
+	if err != nil {
+		t.Errorf("Groups.ListAvailableRealmRoles returned error: %v", err)
+	}
+
+	if hasRole(roles, "role1") || !hasRole(roles, "role2") {
+		t.Errorf("got: %v, want: role2", roles)
+	}
+}
+
+func TestGroupsService_ListClientRoles(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	clientID := createClient(t, k, realm, "client")
+	createClientRole(t, k, realm, clientID, "role1")
+	createClientRole(t, k, realm, clientID, "role2")
+	groupID := createGroup(t, k, realm, "group")
+
+	ctx := context.Background()
+
+	role, _, err := k.ClientRoles.Get(ctx, realm, clientID, "role1")
+	if err != nil {
+		t.Fatalf("ClientRoles.Get returned error: %v", err)
+	}
+	if _, err := k.Groups.AddClientRoles(ctx, realm, groupID, clientID, []*Role{role}); err != nil {
+		t.Errorf("Groups.AddClientRoles returned error: %v", err)
+	}
+
+	roles, _, err := k.Groups.ListClientRoles(ctx, realm, groupID, clientID)
+	if err != nil {
+		t.Errorf("Groups.ListClientRoles returned error: %v", err)
+	}
+
+	if len(roles) != 1 {
+		t.Errorf("got: %d, want: %d", len(roles), 1)
+	}
+
+	roles, _, err = k.Groups.ListCompositeClientRoles(ctx, realm, groupID, clientID)
+	if err != nil {
+		t.Errorf("Groups.ListCompositeClientRoles returned error: %v", err)
+	}
+
+	if !hasRole(roles, "role1") {
+		t.Errorf("got: %v, want: role1", roles)
+	}
+
+	if _, err := k.Groups.RemoveClientRoles(ctx, realm, groupID, clientID, []*Role{role}); err != nil {
+		t.Errorf("Groups.RemoveClientRoles returned error: %v", err)
+	}
+
+	roles, _, err = k.Groups.ListAvailableClientRoles(ctx, realm, groupID, clientID)
+	if err != nil {
+		t.Errorf("Groups.ListAvailableClientRoles returned error: %v", err)
+	}
+
+	if !hasRole(roles, "role1") || !hasRole(roles, "role2") {
+		t.Errorf("got: %v, want: role1 and role2", roles)
+	}
+}
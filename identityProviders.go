@@ -0,0 +1,364 @@
+package keycloak
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// IdentityProvider representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/IdentityProviderRepresentation.java
+type IdentityProvider struct {
+	Alias       *string `json:"alias,omitempty"`
+	DisplayName *string `json:"displayName,omitempty"`
+	InternalID  *string `json:"internalId,omitempty"`
+	// ProviderID is the type of the provider, e.g. "oidc", "saml", "github"
+	// or "google".
+	ProviderID                *string            `json:"providerId,omitempty"`
+	Enabled                   *bool              `json:"enabled,omitempty"`
+	TrustEmail                *bool              `json:"trustEmail,omitempty"`
+	StoreToken                *bool              `json:"storeToken,omitempty"`
+	AddReadTokenRoleOnCreate  *bool              `json:"addReadTokenRoleOnCreate,omitempty"`
+	AuthenticateByDefault     *bool              `json:"authenticateByDefault,omitempty"`
+	LinkOnly                  *bool              `json:"linkOnly,omitempty"`
+	HideOnLogin               *bool              `json:"hideOnLogin,omitempty"`
+	FirstBrokerLoginFlowAlias *string            `json:"firstBrokerLoginFlowAlias,omitempty"`
+	PostBrokerLoginFlowAlias  *string            `json:"postBrokerLoginFlowAlias,omitempty"`
+	Config                    *map[string]string `json:"config,omitempty"`
+}
+
+// IdentityProviderMapper representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/IdentityProviderMapperRepresentation.java
+type IdentityProviderMapper struct {
+	ID                     *string `json:"id,omitempty"`
+	Name                   *string `json:"name,omitempty"`
+	IdentityProviderAlias  *string `json:"identityProviderAlias,omitempty"`
+	IdentityProviderMapper *string `json:"identityProviderMapper,omitempty"`
+	// Config holds the settings of the mapper. The "syncMode" defines when
+	// the mapper runs, i.e. "INHERIT", "IMPORT", "LEGACY" or "FORCE".
+	Config *map[string]string `json:"config,omitempty"`
+}
+
+// IdentityProviderMapperType describes a mapper which can be added to an
+// identity provider.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/IdentityProviderMapperTypeRepresentation.java
+type IdentityProviderMapperType struct {
+	ID         *string           `json:"id,omitempty"`
+	Name       *string           `json:"name,omitempty"`
+	Category   *string           `json:"category,omitempty"`
+	HelpText   *string           `json:"helpText,omitempty"`
+	Properties []*ConfigProperty `json:"properties,omitempty"`
+}
+
+// ConfigProperty representation. It describes a single setting in the
+// config of a provider.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/ConfigPropertyRepresentation.java
+type ConfigProperty struct {
+	Name         *string     `json:"name,omitempty"`
+	Label        *string     `json:"label,omitempty"`
+	HelpText     *string     `json:"helpText,omitempty"`
+	Type         *string     `json:"type,omitempty"`
+	DefaultValue interface{} `json:"defaultValue,omitempty"`
+	Options      []string    `json:"options,omitempty"`
+	Secret       *bool       `json:"secret,omitempty"`
+	Required     *bool       `json:"required,omitempty"`
+	ReadOnly     *bool       `json:"readOnly,omitempty"`
+}
+
+// ManagementPermissionReference representation. It tells whether fine
+// grained admin permissions are enabled for a resource and lists the
+// authorization permissions of the "realm-management" client per scope.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/ManagementPermissionReference.java
+type ManagementPermissionReference struct {
+	Enabled          *bool              `json:"enabled,omitempty"`
+	Resource         *string            `json:"resource,omitempty"`
+	ScopePermissions *map[string]string `json:"scopePermissions,omitempty"`
+}
+
+// IdentityProvidersService handles communication with the identity provider related methods of the Keycloak API.
+type IdentityProvidersService service
+
+// IdentityProvidersListOptions ...
+type IdentityProvidersListOptions struct {
+	// Search is a string contained in the alias. The search is prefix-based
+	// by default. Use *foo* for an infix search.
+	Search              string `url:"search,omitempty"`
+	BriefRepresentation *bool  `url:"briefRepresentation,omitempty"`
+	Options
+}
+
+// Create a new identity provider.
+func (s *IdentityProvidersService) Create(ctx context.Context, realm string, idp *IdentityProvider) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances", realm)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, idp)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// CreateAndGet creates a new identity provider and returns the created identity provider with all its properties.
+// Keycloak identifies the new identity provider by its alias in the "Location" header.
+func (s *IdentityProvidersService) CreateAndGet(ctx context.Context, realm string, idp *IdentityProvider) (*IdentityProvider, *http.Response, error) {
+	res, err := s.Create(ctx, realm, idp)
+	if err != nil {
+		return nil, res, err
+	}
+
+	alias, err := IDFromLocation(res)
+	if err != nil {
+		return nil, res, err
+	}
+
+	return s.Get(ctx, realm, alias)
+}
+
+// List identity providers.
+func (s *IdentityProvidersService) List(ctx context.Context, realm string, opts *IdentityProvidersListOptions) ([]*IdentityProvider, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances", realm)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var idps []*IdentityProvider
+	res, err := s.keycloak.Do(ctx, req, &idps)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return idps, res, nil
+}
+
+// Get identity provider.
+func (s *IdentityProvidersService) Get(ctx context.Context, realm, alias string) (*IdentityProvider, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s", realm, alias)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var idp IdentityProvider
+	res, err := s.keycloak.Do(ctx, req, &idp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &idp, res, nil
+}
+
+// Update identity provider.
+func (s *IdentityProvidersService) Update(ctx context.Context, realm string, idp *IdentityProvider) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s", realm, *idp.Alias)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, idp)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// Delete identity provider.
+func (s *IdentityProvidersService) Delete(ctx context.Context, realm, alias string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s", realm, alias)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// CreateMapper creates a new mapper for the identity provider. The ID of
+// the new mapper is in the "Location" header of the response.
+func (s *IdentityProvidersService) CreateMapper(ctx context.Context, realm, alias string, mapper *IdentityProviderMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/mappers", realm, alias)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListMappers lists the mappers of the identity provider.
+func (s *IdentityProvidersService) ListMappers(ctx context.Context, realm, alias string) ([]*IdentityProviderMapper, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/mappers", realm, alias)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mappers []*IdentityProviderMapper
+	res, err := s.keycloak.Do(ctx, req, &mappers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mappers, res, nil
+}
+
+// GetMapper gets a mapper of the identity provider.
+func (s *IdentityProvidersService) GetMapper(ctx context.Context, realm, alias, id string) (*IdentityProviderMapper, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/mappers/%s", realm, alias, id)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mapper IdentityProviderMapper
+	res, err := s.keycloak.Do(ctx, req, &mapper)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mapper, res, nil
+}
+
+// UpdateMapper updates a mapper of the identity provider.
+func (s *IdentityProvidersService) UpdateMapper(ctx context.Context, realm, alias string, mapper *IdentityProviderMapper) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/mappers/%s", realm, alias, *mapper.ID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, mapper)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// DeleteMapper deletes a mapper of the identity provider.
+func (s *IdentityProvidersService) DeleteMapper(ctx context.Context, realm, alias, id string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/mappers/%s", realm, alias, id)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListMapperTypes returns the mapper types which can be added to the
+// identity provider keyed by their ID.
+func (s *IdentityProvidersService) ListMapperTypes(ctx context.Context, realm, alias string) (map[string]*IdentityProviderMapperType, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/mapper-types", realm, alias)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var types map[string]*IdentityProviderMapperType
+	res, err := s.keycloak.Do(ctx, req, &types)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return types, res, nil
+}
+
+// ImportConfig fetches the OpenID Connect discovery document or the SAML
+// metadata at fromURL and returns the config of an identity provider of type
+// providerID, i.e. "oidc" or "saml". It does not create the identity
+// provider.
+func (s *IdentityProvidersService) ImportConfig(ctx context.Context, realm, providerID, fromURL string) (map[string]string, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/import-config", realm)
+	body := map[string]string{
+		"providerId": providerID,
+		"fromUrl":    fromURL,
+	}
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var config map[string]string
+	res, err := s.keycloak.Do(ctx, req, &config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, res, nil
+}
+
+// ImportConfigFile is like ImportConfig but reads the discovery document or
+// metadata from file.
+func (s *IdentityProvidersService) ImportConfigFile(ctx context.Context, realm, providerID string, file io.Reader) (map[string]string, *http.Response, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("providerId", providerID); err != nil {
+		return nil, nil, err
+	}
+	part, err := w.CreateFormFile("file", "metadata")
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/import-config", realm)
+	req, err := s.keycloak.NewRawRequest(http.MethodPost, u, body.Bytes(), w.FormDataContentType())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var config map[string]string
+	res, err := s.keycloak.Do(ctx, req, &config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, res, nil
+}
+
+// GetManagementPermissions returns whether fine grained admin permissions
+// are enabled for the identity provider.
+func (s *IdentityProvidersService) GetManagementPermissions(ctx context.Context, realm, alias string) (*ManagementPermissionReference, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/management/permissions", realm, alias)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var permissions ManagementPermissionReference
+	res, err := s.keycloak.Do(ctx, req, &permissions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &permissions, res, nil
+}
+
+// SetManagementPermissions enables or disables fine grained admin
+// permissions for the identity provider.
+func (s *IdentityProvidersService) SetManagementPermissions(ctx context.Context, realm, alias string, enabled bool) (*ManagementPermissionReference, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/identity-provider/instances/%s/management/permissions", realm, alias)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, &ManagementPermissionReference{Enabled: Bool(enabled)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var permissions ManagementPermissionReference
+	res, err := s.keycloak.Do(ctx, req, &permissions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &permissions, res, nil
+}
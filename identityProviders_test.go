@@ -0,0 +1,297 @@
+package keycloak
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func createIdentityProvider(t *testing.T, k *Keycloak, realm string, alias string) {
+	t.Helper()
+
+	idp := &IdentityProvider{
+		Alias:      String(alias),
+		ProviderID: String("oidc"),
+		Enabled:    Bool(true),
+		Config: &map[string]string{
+			"clientId":         "client",
+			"clientSecret":     "secret",
+			"authorizationUrl": "https://example.com/auth",
+			"tokenUrl":         "https://example.com/token",
+		},
+	}
+
+	if _, err := k.IdentityProviders.Create(context.Background(), realm, idp); err != nil {
+		t.Errorf("IdentityProviders.Create returned error: %v", err)
+	}
+}
+
+func TestIdentityProvidersService_Create(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	idp := &IdentityProvider{
+		Alias:      String("oidc"),
+		ProviderID: String("oidc"),
+		Enabled:    Bool(true),
+	}
+
+	res, err := k.IdentityProviders.Create(context.Background(), realm, idp)
+	if err != nil {
+		t.Errorf("IdentityProviders.Create returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusCreated)
+	}
+}
+
+func TestIdentityProvidersService_List(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createIdentityProvider(t, k, realm, "first")
+	createIdentityProvider(t, k, realm, "second")
+
+	idps, res, err := k.IdentityProviders.List(context.Background(), realm, nil)
+	if err != nil {
+		t.Errorf("IdentityProviders.List returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(idps) != 2 {
+		t.Errorf("got: %d, want: %d", len(idps), 2)
+	}
+}
+
+func TestIdentityProvidersService_Get(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createIdentityProvider(t, k, realm, "oidc")
+
+	idp, res, err := k.IdentityProviders.Get(context.Background(), realm, "oidc")
+	if err != nil {
+		t.Fatalf("IdentityProviders.Get returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *idp.ProviderID != "oidc" {
+		t.Errorf("got: %s, want: %s", *idp.ProviderID, "oidc")
+	}
+}
+
+func TestIdentityProvidersService_Update(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createIdentityProvider(t, k, realm, "oidc")
+
+	ctx := context.Background()
+
+	idp, _, err := k.IdentityProviders.Get(ctx, realm, "oidc")
+	if err != nil {
+		t.Fatalf("IdentityProviders.Get returned error: %v", err)
+	}
+
+	idp.DisplayName = String("OpenID Connect")
+
+	res, err := k.IdentityProviders.Update(ctx, realm, idp)
+	if err != nil {
+		t.Errorf("IdentityProviders.Update returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	idp, _, err = k.IdentityProviders.Get(ctx, realm, "oidc")
+	if err != nil {
+		t.Fatalf("IdentityProviders.Get returned error: %v", err)
+	}
+
+	if *idp.DisplayName != "OpenID Connect" {
+		t.Errorf("got: %s, want: %s", *idp.DisplayName, "OpenID Connect")
+	}
+}
+
+func TestIdentityProvidersService_Delete(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createIdentityProvider(t, k, realm, "oidc")
+
+	res, err := k.IdentityProviders.Delete(context.Background(), realm, "oidc")
+	if err != nil {
+		t.Errorf("IdentityProviders.Delete returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestIdentityProvidersService_Mappers(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createIdentityProvider(t, k, realm, "oidc")
+
+	ctx := context.Background()
+
+	mapper := &IdentityProviderMapper{
+		Name:                   String("email"),
+		IdentityProviderAlias:  String("oidc"),
+		IdentityProviderMapper: String("oidc-user-attribute-idp-mapper"),
+		Config: &map[string]string{
+			"syncMode":       "INHERIT",
+			"claim":          "email",
+			"user.attribute": "email",
+		},
+	}
+
+	res, err := k.IdentityProviders.CreateMapper(ctx, realm, "oidc", mapper)
+	if err != nil {
+		t.Fatalf("IdentityProviders.CreateMapper returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusCreated)
+	}
+
+	mappers, _, err := k.IdentityProviders.ListMappers(ctx, realm, "oidc")
+	if err != nil {
+		t.Fatalf("IdentityProviders.ListMappers returned error: %v", err)
+	}
+
+	if len(mappers) != 1 {
+		t.Fatalf("got: %d, want: %d", len(mappers), 1)
+	}
+
+	mapper = mappers[0]
+	(*mapper.Config)["claim"] = "mail"
+
+	if _, err := k.IdentityProviders.UpdateMapper(ctx, realm, "oidc", mapper); err != nil {
+		t.Errorf("IdentityProviders.UpdateMapper returned error: %v", err)
+	}
+
+	mapper, _, err = k.IdentityProviders.GetMapper(ctx, realm, "oidc", *mapper.ID)
+	if err != nil {
+		t.Fatalf("IdentityProviders.GetMapper returned error: %v", err)
+	}
+
+	if (*mapper.Config)["claim"] != "mail" {
+		t.Errorf("got: %s, want: %s", (*mapper.Config)["claim"], "mail")
+	}
+
+	res, err = k.IdentityProviders.DeleteMapper(ctx, realm, "oidc", *mapper.ID)
+	if err != nil {
+		t.Errorf("IdentityProviders.DeleteMapper returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestIdentityProvidersService_ListMapperTypes(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createIdentityProvider(t, k, realm, "oidc")
+
+	types, _, err := k.IdentityProviders.ListMapperTypes(context.Background(), realm, "oidc")
+	if err != nil {
+		t.Fatalf("IdentityProviders.ListMapperTypes returned error: %v", err)
+	}
+
+	if types["oidc-user-attribute-idp-mapper"] == nil {
+		t.Errorf("got: nil, want: %s", "oidc-user-attribute-idp-mapper")
+	}
+}
+
+func TestIdentityProvidersService_ImportConfig(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	ctx := context.Background()
+
+	config, _, err := k.IdentityProviders.ImportConfig(ctx, realm, "oidc", "http://localhost:8080/realms/master/.well-known/openid-configuration")
+	if err != nil {
+		t.Fatalf("IdentityProviders.ImportConfig returned error: %v", err)
+	}
+
+	if config["tokenUrl"] != "http://localhost:8080/realms/master/protocol/openid-connect/token" {
+		t.Errorf("got: %s, want: %s", config["tokenUrl"], "http://localhost:8080/realms/master/protocol/openid-connect/token")
+	}
+
+	discovery := strings.NewReader(`{
+		"issuer": "https://example.com",
+		"authorization_endpoint": "https://example.com/auth",
+		"token_endpoint": "https://example.com/token",
+		"jwks_uri": "https://example.com/jwks"
+	}`)
+
+	config, _, err = k.IdentityProviders.ImportConfigFile(ctx, realm, "oidc", discovery)
+	if err != nil {
+		t.Fatalf("IdentityProviders.ImportConfigFile returned error: %v", err)
+	}
+
+	if config["jwksUrl"] != "https://example.com/jwks" {
+		t.Errorf("got: %s, want: %s", config["jwksUrl"], "https://example.com/jwks")
+	}
+}
+
+func TestIdentityProvidersService_FederatedIdentities(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createIdentityProvider(t, k, realm, "oidc")
+	userID := createUser(t, k, realm, "user")
+
+	ctx := context.Background()
+
+	link := &FederatedIdentity{
+		UserID:   String("12345"),
+		UserName: String("user@example.com"),
+	}
+
+	if _, err := k.Users.AddFederatedIdentity(ctx, realm, userID, "oidc", link); err != nil {
+		t.Fatalf("Users.AddFederatedIdentity returned error: %v", err)
+	}
+
+	identities, _, err := k.Users.ListFederatedIdentities(ctx, realm, userID)
+	if err != nil {
+		t.Fatalf("Users.ListFederatedIdentities returned error: %v", err)
+	}
+
+	if len(identities) != 1 {
+		t.Fatalf("got: %d, want: %d", len(identities), 1)
+	}
+
+	if *identities[0].UserName != "user@example.com" {
+		t.Errorf("got: %s, want: %s", *identities[0].UserName, "user@example.com")
+	}
+
+	if _, err := k.Users.RemoveFederatedIdentity(ctx, realm, userID, "oidc"); err != nil {
+		t.Errorf("Users.RemoveFederatedIdentity returned error: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,19 +21,26 @@ type Keycloak struct {
 
 	BaseURL *url.URL
 
+	// RetryPolicy controls how failed requests are retried. Requests are not
+	// retried if it is nil.
+	RetryPolicy *RetryPolicy
+
 	common service
 
-	Clients      *ClientsService
-	ClientRoles  *ClientRolesService
-	ClientScopes *ClientScopesService
-	Groups       *GroupsService
-	Permissions  *PermissionsService
-	Policies     *PoliciesService
-	Realms       *RealmsService
-	RealmRoles   *RealmRolesService
-	Resources    *ResourcesService
-	Scopes       *ScopesService
-	Users        *UsersService
+	AttackDetection   *AttackDetectionService
+	Clients           *ClientsService
+	ClientRoles       *ClientRolesService
+	ClientScopes      *ClientScopesService
+	Events            *EventsService
+	Groups            *GroupsService
+	IdentityProviders *IdentityProvidersService
+	Permissions       *PermissionsService
+	Policies          *PoliciesService
+	Realms            *RealmsService
+	RealmRoles        *RealmRolesService
+	Resources         *ResourcesService
+	Scopes            *ScopesService
+	Users             *UsersService
 }
 
 type service struct {
@@ -40,7 +48,8 @@ type service struct {
 }
 
 // addOptions adds the parameters in opts as URL query parameters to s. opts
-// must be a struct whose fields may contain "url" tags.
+// must be a struct whose fields may contain "url" tags. Query parameters
+// already present in s are kept unless opts overrides them.
 func addOptions(s string, opts interface{}) (string, error) {
 	v := reflect.ValueOf(opts)
 	if v.Kind() == reflect.Ptr && v.IsNil() {
@@ -57,7 +66,13 @@ func addOptions(s string, opts interface{}) (string, error) {
 		return s, err
 	}
 
-	u.RawQuery = qs.Encode()
+	// keep query parameters which are already part of s
+	q := u.Query()
+	for k, v := range qs {
+		q[k] = v
+	}
+
+	u.RawQuery = q.Encode()
 	return u.String(), nil
 }
 
@@ -77,10 +92,13 @@ func NewKeycloak(httpClient *http.Client, baseURL string) (*Keycloak, error) {
 	}
 
 	k.common.keycloak = k
+	k.AttackDetection = (*AttackDetectionService)(&k.common)
 	k.Clients = (*ClientsService)(&k.common)
 	k.ClientRoles = (*ClientRolesService)(&k.common)
 	k.ClientScopes = (*ClientScopesService)(&k.common)
+	k.Events = (*EventsService)(&k.common)
 	k.Groups = (*GroupsService)(&k.common)
+	k.IdentityProviders = (*IdentityProvidersService)(&k.common)
 	k.Permissions = (*PermissionsService)(&k.common)
 	k.Policies = (*PoliciesService)(&k.common)
 	k.Realms = (*RealmsService)(&k.common)
@@ -94,6 +112,21 @@ func NewKeycloak(httpClient *http.Client, baseURL string) (*Keycloak, error) {
 
 // NewRequest ...
 func (k *Keycloak) NewRequest(method string, url string, body interface{}) (*http.Request, error) {
+	if body == nil {
+		return k.NewRawRequest(method, url, nil, "")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(body); err != nil {
+		return nil, err
+	}
+	return k.NewRawRequest(method, url, buf.Bytes(), "application/json")
+}
+
+// NewRawRequest creates an API request with a body which is sent as is, e.g.
+// plain text or multipart form data. The Content-Type header is set to
+// contentType if body is not nil.
+func (k *Keycloak) NewRawRequest(method string, url string, body []byte, contentType string) (*http.Request, error) {
 	if !strings.HasSuffix(k.BaseURL.Path, "/") {
 		return nil, fmt.Errorf("BaseURL must have a trailing slash, but %q does not", k.BaseURL)
 	}
@@ -102,12 +135,11 @@ func (k *Keycloak) NewRequest(method string, url string, body interface{}) (*htt
 		return nil, err
 	}
 
-	var b io.ReadWriter
+	// use a *bytes.Reader so the request body can be replayed when the request
+	// is retried
+	var b io.Reader
 	if body != nil {
-		b = &bytes.Buffer{}
-		if err := json.NewEncoder(b).Encode(body); err != nil {
-			return nil, err
-		}
+		b = bytes.NewReader(body)
 	}
 
 	req, err := http.NewRequest(method, u.String(), b)
@@ -116,42 +148,169 @@ func (k *Keycloak) NewRequest(method string, url string, body interface{}) (*htt
 	}
 
 	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	return req, nil
 }
 
-// Do ...
+// Do sends an API request and stores the JSON decoded response body in v.
+// Responses with a status code outside the 200 range are returned together
+// with an *ErrorResponse. Failed requests are retried according to the
+// RetryPolicy.
 func (k *Keycloak) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
 	req = req.WithContext(ctx)
 
-	res, err := k.client.Do(req)
+	res, err := k.do(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
+	if err := CheckResponse(res); err != nil {
+		return res, err
+	}
+
 	if v != nil {
-		if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		if err := json.NewDecoder(res.Body).Decode(v); err != nil && err != io.EOF {
 			return nil, err
 		}
 	}
 
-	return res, err
+	return res, nil
+}
+
+// ErrorResponse reports an error caused by a Keycloak API request.
+//
+// Depending on the endpoint Keycloak describes the error in one of the fields
+// "error", "errorMessage" or "error_description".
+type ErrorResponse struct {
+	// Response is the HTTP response that caused this error.
+	Response *http.Response `json:"-"`
+
+	Err              string `json:"error,omitempty"`
+	ErrorMessage     string `json:"errorMessage,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+func (r *ErrorResponse) Error() string {
+	msg := r.ErrorMessage
+	if msg == "" {
+		msg = r.Err
+	}
+	if r.ErrorDescription != "" {
+		if msg != "" {
+			msg += ": "
+		}
+		msg += r.ErrorDescription
+	}
+	if r.Response == nil {
+		if msg == "" {
+			msg = "keycloak: unknown error"
+		}
+		return msg
+	}
+	if msg == "" {
+		msg = http.StatusText(r.Response.StatusCode)
+	}
+	// responses which are not created by http.Client have no request
+	if r.Response.Request == nil {
+		return fmt.Sprintf("%d %v", r.Response.StatusCode, msg)
+	}
+	return fmt.Sprintf("%v %v: %d %v", r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, msg)
+}
+
+// CheckResponse checks the API response for errors and returns them if present.
+// A response is considered an error if it has a status code outside the 200 range.
+// The JSON error body, if any, is decoded into an *ErrorResponse.
+func CheckResponse(res *http.Response) error {
+	if c := res.StatusCode; 200 <= c && c <= 299 {
+		return nil
+	}
+
+	errorResponse := &ErrorResponse{Response: res}
+	data, err := io.ReadAll(res.Body)
+	if err == nil && len(data) > 0 {
+		// the body is not always json, e.g. for some 404 responses
+		_ = json.Unmarshal(data, errorResponse)
+	}
+	return errorResponse
+}
+
+// ErrNotFound is returned by lookup helpers like ClientsService.GetByClientID
+// when Keycloak answers successfully but nothing matches.
+var ErrNotFound = errors.New("keycloak: not found")
+
+// IsNotFound reports whether err is an *ErrorResponse with status 404 Not Found
+// or wraps ErrNotFound.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an *ErrorResponse with status 409 Conflict.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsUnauthorized reports whether err is an *ErrorResponse with status 401 Unauthorized.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an *ErrorResponse with status 403 Forbidden.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+func hasStatus(err error, code int) bool {
+	var errorResponse *ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.Response != nil && errorResponse.Response.StatusCode == code
+	}
+	return false
+}
+
+// IDFromLocation returns the last path segment of the "Location" header. Keycloak
+// sets this header on most create calls and it points to the newly created
+// resource, i.e. the last segment is the ID of the resource.
+func IDFromLocation(res *http.Response) (string, error) {
+	if res == nil {
+		return "", errors.New("keycloak: response is nil")
+	}
+
+	location := res.Header.Get("Location")
+	if location == "" {
+		return "", errors.New("keycloak: response has no Location header")
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+
+	path := strings.TrimSuffix(u.EscapedPath(), "/")
+	id, err := url.PathUnescape(path[strings.LastIndex(path, "/")+1:])
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", fmt.Errorf("keycloak: cannot find ID in Location header %q", location)
+	}
+
+	return id, nil
 }
 
 // Bool is a helper routine that allocates a new bool value
 // to store v and returns a pointer to it.
 func Bool(v bool) *bool { return &v }
 
-// // Int is a helper routine that allocates a new int value
-// // to store v and returns a pointer to it.
-// func Int(v int) *int { return &v }
+// Int is a helper routine that allocates a new int value
+// to store v and returns a pointer to it.
+func Int(v int) *int { return &v }
 
-// // Int64 is a helper routine that allocates a new int64 value
-// // to store v and returns a pointer to it.
-// func Int64(v int64) *int64 { return &v }
+// Int64 is a helper routine that allocates a new int64 value
+// to store v and returns a pointer to it.
+func Int64(v int64) *int64 { return &v }
 
 // String is a helper routine that allocates a new string value
 // to store v and returns a pointer to it.
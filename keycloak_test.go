@@ -2,46 +2,198 @@ package keycloak
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
-
-	"golang.org/x/oauth2"
 )
 
 // create a new keycloak instance.
 func client(t *testing.T) *Keycloak {
 	t.Helper()
 
-	config := oauth2.Config{
-		ClientID: "admin-cli",
-		Endpoint: oauth2.Endpoint{
-			TokenURL: "http://localhost:8080/realms/master/protocol/openid-connect/token",
-		},
+	kc, err := NewWithPassword(context.Background(), "http://localhost:8080/", "master", "admin-cli", "admin", "admin")
+	if err != nil {
+		t.Fatal(err)
 	}
+	return kc
+}
+
+func TestKeycloak_New(t *testing.T) {
+
+}
+
+func TestKeycloak_NewRequest(t *testing.T) {
+
+}
+
+// create a new keycloak instance backed by a local test server.
+func setup(t *testing.T, handler http.HandlerFunc) *Keycloak {
+	t.Helper()
 
-	ctx := context.Background()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
 
-	token, err := config.PasswordCredentialsToken(ctx, "admin", "admin")
+	kc, err := NewKeycloak(nil, server.URL+"/")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
+	return kc
+}
 
-	client := config.Client(ctx, token)
+func TestKeycloak_Do(t *testing.T) {
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"first","realm":"first"}`)
+	})
 
-	kc, err := NewKeycloak(client, "http://localhost:8080/")
+	req, err := k.NewRequest(http.MethodGet, "admin/realms/first", nil)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+
+	var realm Realm
+	res, err := k.Do(context.Background(), req, &realm)
+	if err != nil {
+		t.Errorf("Do returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *realm.ID != "first" {
+		t.Errorf("got: %s, want: %s", *realm.ID, "first")
 	}
-	return kc
 }
 
-func TestKeycloak_New(t *testing.T) {
+func TestKeycloak_Do_noContent(t *testing.T) {
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req, err := k.NewRequest(http.MethodPut, "admin/realms/first", &Realm{})
+	if err != nil {
+		t.Fatal(err)
+	}
 
+	var realm Realm
+	if _, err := k.Do(context.Background(), req, &realm); err != nil {
+		t.Errorf("Do returned error: %v", err)
+	}
 }
 
-func TestKeycloak_NewRequest(t *testing.T) {
+func TestKeycloak_Do_errorResponse(t *testing.T) {
+	tests := []struct {
+		status       int
+		body         string
+		message      string
+		notFound     bool
+		conflict     bool
+		forbidden    bool
+		unauthorized bool
+	}{
+		{http.StatusNotFound, `{"error":"Could not find client"}`, "Could not find client", true, false, false, false},
+		{http.StatusConflict, `{"errorMessage":"User exists with same username"}`, "User exists with same username", false, true, false, false},
+		{http.StatusUnauthorized, `{"error":"HTTP 401 Unauthorized"}`, "HTTP 401 Unauthorized", false, false, false, true},
+		{http.StatusBadRequest, `{"error":"invalid_grant","error_description":"Invalid user credentials"}`, "invalid_grant: Invalid user credentials", false, false, false, false},
+		{http.StatusForbidden, ``, "Forbidden", false, false, true, false},
+		{http.StatusInternalServerError, `<html>oops</html>`, "Internal Server Error", false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprint(w, tt.body)
+		})
+
+		req, err := k.NewRequest(http.MethodGet, "admin/realms/first/clients/id", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var client Client
+		res, err := k.Do(context.Background(), req, &client)
+		if err == nil {
+			t.Fatalf("Do returned no error for status %d", tt.status)
+		}
 
+		errorResponse, ok := err.(*ErrorResponse)
+		if !ok {
+			t.Fatalf("got: %T, want: %T", err, errorResponse)
+		}
+
+		if res.StatusCode != tt.status {
+			t.Errorf("got: %d, want: %d", res.StatusCode, tt.status)
+		}
+
+		want := fmt.Sprintf("GET %sadmin/realms/first/clients/id: %d %s", k.BaseURL, tt.status, tt.message)
+		if err.Error() != want {
+			t.Errorf("got: %s, want: %s", err.Error(), want)
+		}
+
+		if IsNotFound(err) != tt.notFound {
+			t.Errorf("IsNotFound got: %t, want: %t", IsNotFound(err), tt.notFound)
+		}
+		if IsConflict(err) != tt.conflict {
+			t.Errorf("IsConflict got: %t, want: %t", IsConflict(err), tt.conflict)
+		}
+		if IsForbidden(err) != tt.forbidden {
+			t.Errorf("IsForbidden got: %t, want: %t", IsForbidden(err), tt.forbidden)
+		}
+		if IsUnauthorized(err) != tt.unauthorized {
+			t.Errorf("IsUnauthorized got: %t, want: %t", IsUnauthorized(err), tt.unauthorized)
+		}
+	}
 }
 
-func TestKeycloak_Do(t *testing.T) {
+func TestErrorResponse_Error(t *testing.T) {
+	err := &ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	if got := err.Error(); got != "404 Not Found" {
+		t.Errorf("got: %s, want: %s", got, "404 Not Found")
+	}
+
+	err.ErrorMessage = "User not found"
+	if got := err.Error(); got != "404 User not found" {
+		t.Errorf("got: %s, want: %s", got, "404 User not found")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if IsNotFound(nil) {
+		t.Errorf("got: %t, want: %t", true, false)
+	}
+
+	if IsNotFound(fmt.Errorf("wrapped: %w", &ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}})) != true {
+		t.Errorf("got: %t, want: %t", false, true)
+	}
+
+	if IsNotFound(fmt.Errorf("wrapped: %w", ErrNotFound)) != true {
+		t.Errorf("got: %t, want: %t", false, true)
+	}
+}
+
+func TestIDFromLocation(t *testing.T) {
+	tests := []struct {
+		location string
+		id       string
+	}{
+		{"http://localhost:8080/admin/realms/first/users/1a4b53ea-3ba7-4a8a-8e2a-8c1ad0ef4cd8", "1a4b53ea-3ba7-4a8a-8e2a-8c1ad0ef4cd8"},
+		{"http://localhost:8080/admin/realms/first/roles/my%20role", "my role"},
+		{"http://localhost:8080/admin/realms/first/", "first"},
+	}
 
+	for _, tt := range tests {
+		res := &http.Response{Header: http.Header{"Location": {tt.location}}}
+		id, err := IDFromLocation(res)
+		if err != nil {
+			t.Errorf("IDFromLocation returned error: %v", err)
+		}
+		if id != tt.id {
+			t.Errorf("got: %s, want: %s", id, tt.id)
+		}
+	}
+
+	if _, err := IDFromLocation(&http.Response{Header: http.Header{}}); err == nil {
+		t.Error("IDFromLocation returned no error for missing Location header")
+	}
 }
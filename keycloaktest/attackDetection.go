@@ -0,0 +1,35 @@
+package keycloaktest
+
+import "net/http"
+
+func (s *Server) registerAttackDetection() {
+	base := "admin/realms/{realm}/attack-detection/brute-force/users"
+	s.handle(http.MethodDelete, base, s.clearAllBruteForce)
+	s.handle(http.MethodGet, base+"/{id}", s.getBruteForceStatus)
+	s.handle(http.MethodDelete, base+"/{id}", s.clearBruteForce)
+}
+
+// getBruteForceStatus always returns the status of a user without login
+// failures since the fake has no login.
+func (s *Server) getBruteForceStatus(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, object{
+		"numFailures":   0,
+		"disabled":      false,
+		"lastIPFailure": "n/a",
+		"lastFailure":   0,
+	})
+}
+
+func (s *Server) clearBruteForce(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	noContent(w)
+}
+
+func (s *Server) clearAllBruteForce(w http.ResponseWriter, r *request) {
+	noContent(w)
+}
@@ -0,0 +1,225 @@
+package keycloaktest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// permissionTypes are the policy types which are permissions.
+var permissionTypes = map[string]bool{
+	"resource": true,
+	"scope":    true,
+}
+
+func (s *Server) registerAuthz() {
+	base := "admin/realms/{realm}/clients/{client}/authz/resource-server"
+	s.handle(http.MethodGet, base+"/resource", s.listAuthz("resource"))
+	s.handle(http.MethodPost, base+"/resource", s.postAuthz("resource"))
+	s.handle(http.MethodGet, base+"/resource/{id}", s.getAuthz("resource"))
+	s.handle(http.MethodPut, base+"/resource/{id}", s.updateAuthz("resource"))
+	s.handle(http.MethodDelete, base+"/resource/{id}", s.deleteAuthz("resource"))
+
+	s.handle(http.MethodGet, base+"/scope", s.listAuthz("scope"))
+	s.handle(http.MethodPost, base+"/scope", s.postAuthz("scope"))
+	s.handle(http.MethodGet, base+"/scope/{id}", s.getAuthz("scope"))
+	s.handle(http.MethodPut, base+"/scope/{id}", s.updateAuthz("scope"))
+	s.handle(http.MethodDelete, base+"/scope/{id}", s.deleteAuthz("scope"))
+
+	// policies and permissions share the same storage, the path segment after
+	// "policy" or "permission" is either the type for POST or the ID
+	for _, kind := range []string{"policy", "permission"} {
+		s.handle(http.MethodGet, base+"/"+kind, s.listAuthz(kind))
+		s.handle(http.MethodPost, base+"/"+kind+"/{type}", s.postAuthz(kind))
+		s.handle(http.MethodGet, base+"/"+kind+"/{id}", s.getAuthz(kind))
+		s.handle(http.MethodPut, base+"/"+kind+"/{id}", s.updateAuthz(kind))
+		s.handle(http.MethodDelete, base+"/"+kind+"/{id}", s.deleteAuthz(kind))
+		s.handle(http.MethodGet, base+"/"+kind+"/{type}/{id}", s.getAuthz(kind))
+		s.handle(http.MethodPut, base+"/"+kind+"/{type}/{id}", s.updateAuthz(kind))
+		s.handle(http.MethodDelete, base+"/"+kind+"/{type}/{id}", s.deleteAuthz(kind))
+	}
+}
+
+// newResourceServer returns the authorization settings Keycloak creates
+// when authorization services are enabled for client.
+func newResourceServer(client object) *resourceServer {
+	rs := &resourceServer{
+		resources: newCollection("_id"),
+		scopes:    newCollection("id"),
+		policies:  newCollection("id"),
+	}
+
+	resourceType := "urn:" + str(client, "clientId") + ":resources:default"
+	rs.resources.add(object{
+		"name":  "Default Resource",
+		"type":  resourceType,
+		"uris":  []string{"/*"},
+		"owner": object{"id": str(client, "id"), "name": str(client, "clientId")},
+	})
+	policyID := rs.policies.add(object{
+		"name":             "Default Policy",
+		"description":      "A policy that grants access only for users within this realm",
+		"type":             "js",
+		"logic":            "POSITIVE",
+		"decisionStrategy": "AFFIRMATIVE",
+	})
+	rs.policies.add(object{
+		"name":             "Default Permission",
+		"description":      "A permission that applies to the default resource type",
+		"type":             "resource",
+		"logic":            "POSITIVE",
+		"decisionStrategy": "UNANIMOUS",
+		"resourceType":     resourceType,
+		"policies":         []string{policyID},
+	})
+	return rs
+}
+
+// collection returns the collection for kind, i.e. "resource", "scope",
+// "policy" or "permission".
+func (rs *resourceServer) collection(kind string) *collection {
+	switch kind {
+	case "resource":
+		return rs.resources
+	case "scope":
+		return rs.scopes
+	default:
+		return rs.policies
+	}
+}
+
+// resourceServer returns the authorization settings of the client with the
+// path variable "client" or writes an error.
+func (s *Server) resourceServer(w http.ResponseWriter, r *request) *resourceServer {
+	if s.client(w, r) == nil {
+		return nil
+	}
+	rs := r.realm.resourceServers[r.vars["client"]]
+	if rs == nil {
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+	}
+	return rs
+}
+
+// matchesKind reports whether o belongs to kind. Permissions are the
+// policies with a permission type, everything else always matches.
+func matchesKind(o object, kind string) bool {
+	return kind != "permission" || permissionTypes[str(o, "type")]
+}
+
+func (s *Server) listAuthz(kind string) handler {
+	return func(w http.ResponseWriter, r *request) {
+		rs := s.resourceServer(w, r)
+		if rs == nil {
+			return
+		}
+
+		q := r.URL.Query()
+		name := strings.ToLower(q.Get("name"))
+		permission := q.Get("permission")
+
+		items := rs.collection(kind).filter(func(o object) bool {
+			if !matchesKind(o, kind) {
+				return false
+			}
+			if kind == "policy" && permission != "" && permissionTypes[str(o, "type")] != (permission == "true") {
+				return false
+			}
+			return strings.Contains(strings.ToLower(str(o, "name")), name)
+		})
+		writeJSON(w, http.StatusOK, paginate(items, r))
+	}
+}
+
+func (s *Server) postAuthz(kind string) handler {
+	return func(w http.ResponseWriter, r *request) {
+		rs := s.resourceServer(w, r)
+		if rs == nil {
+			return
+		}
+
+		var o object
+		if !decode(w, r, &o) {
+			return
+		}
+
+		c := rs.collection(kind)
+		delete(o, c.idKey)
+
+		name := str(o, "name")
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "Name is missing")
+			return
+		}
+		if c.find("name", name) != nil {
+			writeJSON(w, http.StatusConflict, object{
+				"error":             "invalid_request",
+				"error_description": strings.ToUpper(kind[:1]) + kind[1:] + " with name [" + name + "] already exists.",
+			})
+			return
+		}
+
+		if t, ok := r.vars["type"]; ok {
+			o["type"] = t
+		}
+		if kind == "resource" {
+			if _, ok := o["owner"]; !ok {
+				client := r.realm.clients.get(r.vars["client"])
+				o["owner"] = object{"id": str(client, "id"), "name": str(client, "clientId")}
+			}
+		}
+		c.add(o)
+		writeJSON(w, http.StatusCreated, o)
+	}
+}
+
+// authz returns the entity with the path variable "id" or writes a 404
+// error.
+func (s *Server) authz(w http.ResponseWriter, r *request, kind string) (*collection, object) {
+	rs := s.resourceServer(w, r)
+	if rs == nil {
+		return nil, nil
+	}
+	c := rs.collection(kind)
+	o := c.get(r.vars["id"])
+	if o == nil || !matchesKind(o, kind) {
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+		return nil, nil
+	}
+	return c, o
+}
+
+func (s *Server) getAuthz(kind string) handler {
+	return func(w http.ResponseWriter, r *request) {
+		if _, o := s.authz(w, r, kind); o != nil {
+			writeJSON(w, http.StatusOK, o)
+		}
+	}
+}
+
+func (s *Server) updateAuthz(kind string) handler {
+	return func(w http.ResponseWriter, r *request) {
+		c, o := s.authz(w, r, kind)
+		if o == nil {
+			return
+		}
+
+		var update object
+		if !decode(w, r, &update) {
+			return
+		}
+		delete(update, "type")
+		merge(o, update, c.idKey)
+		noContent(w)
+	}
+}
+
+func (s *Server) deleteAuthz(kind string) handler {
+	return func(w http.ResponseWriter, r *request) {
+		c, o := s.authz(w, r, kind)
+		if o == nil {
+			return
+		}
+		c.remove(str(o, c.idKey))
+		noContent(w)
+	}
+}
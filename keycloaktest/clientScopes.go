@@ -0,0 +1,142 @@
+package keycloaktest
+
+import (
+	"net/http"
+)
+
+func (s *Server) registerClientScopes() {
+	base := "admin/realms/{realm}/client-scopes"
+	s.handle(http.MethodGet, base, s.listClientScopes)
+	s.handle(http.MethodPost, base, s.postClientScope)
+	s.handle(http.MethodGet, base+"/{scope}", s.getClientScope)
+	s.handle(http.MethodPut, base+"/{scope}", s.updateClientScope)
+	s.handle(http.MethodDelete, base+"/{scope}", s.deleteClientScope)
+
+	for _, kind := range []string{"default", "optional"} {
+		clients := "admin/realms/{realm}/clients/{client}/" + kind + "-client-scopes"
+		s.handle(http.MethodGet, clients, s.listAssignedScopes(kind))
+		s.handle(http.MethodPut, clients+"/{scope}", s.assignScope(kind))
+		s.handle(http.MethodDelete, clients+"/{scope}", s.unassignScope)
+
+		realm := "admin/realms/{realm}/default-" + kind + "-client-scopes"
+		s.handle(http.MethodGet, realm, s.listAssignedScopes(kind))
+		s.handle(http.MethodPut, realm+"/{scope}", s.assignScope(kind))
+		s.handle(http.MethodDelete, realm+"/{scope}", s.unassignScope)
+	}
+}
+
+// scopeOwner returns the ID of the client with the path variable "client"
+// or, without that variable, the ID of the realm. It writes a 404 error if
+// the client does not exist.
+func (s *Server) scopeOwner(w http.ResponseWriter, r *request) (string, bool) {
+	if _, ok := r.vars["client"]; !ok {
+		return str(r.realm.rep, "id"), true
+	}
+	if s.client(w, r) == nil {
+		return "", false
+	}
+	return r.vars["client"], true
+}
+
+func (s *Server) listAssignedScopes(kind string) handler {
+	return func(w http.ResponseWriter, r *request) {
+		id, ok := s.scopeOwner(w, r)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, r.realm.assignedScopes(id, kind))
+	}
+}
+
+func (s *Server) assignScope(kind string) handler {
+	return func(w http.ResponseWriter, r *request) {
+		id, ok := s.scopeOwner(w, r)
+		if !ok {
+			return
+		}
+		if s.clientScope(w, r) == nil {
+			return
+		}
+		r.realm.assignScope(id, r.vars["scope"], kind)
+		noContent(w)
+	}
+}
+
+func (s *Server) unassignScope(w http.ResponseWriter, r *request) {
+	id, ok := s.scopeOwner(w, r)
+	if !ok {
+		return
+	}
+	if s.clientScope(w, r) == nil {
+		return
+	}
+	delete(r.realm.scopeAssignments[id], r.vars["scope"])
+	noContent(w)
+}
+
+// clientScope returns the client scope with the path variable "scope" or
+// writes a 404 error.
+func (s *Server) clientScope(w http.ResponseWriter, r *request) object {
+	scope := r.realm.clientScopes.get(r.vars["scope"])
+	if scope == nil {
+		writeError(w, http.StatusNotFound, "Could not find client scope")
+	}
+	return scope
+}
+
+func (s *Server) listClientScopes(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, r.realm.clientScopes.filter(nil))
+}
+
+func (s *Server) postClientScope(w http.ResponseWriter, r *request) {
+	var scope object
+	if !decode(w, r, &scope) {
+		return
+	}
+	delete(scope, "id")
+
+	name := str(scope, "name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Client scope name is missing")
+		return
+	}
+	if r.realm.clientScopes.find("name", name) != nil {
+		writeConflict(w, "Client Scope "+name+" already exists")
+		return
+	}
+
+	id := r.realm.clientScopes.add(scope)
+	created(w, s.location("admin", "realms", r.realm.name(), "client-scopes", id))
+}
+
+func (s *Server) getClientScope(w http.ResponseWriter, r *request) {
+	if scope := s.clientScope(w, r); scope != nil {
+		writeJSON(w, http.StatusOK, scope)
+	}
+}
+
+func (s *Server) updateClientScope(w http.ResponseWriter, r *request) {
+	scope := s.clientScope(w, r)
+	if scope == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	merge(scope, update, "id")
+	noContent(w)
+}
+
+func (s *Server) deleteClientScope(w http.ResponseWriter, r *request) {
+	if s.clientScope(w, r) == nil {
+		return
+	}
+	r.realm.clientScopes.remove(r.vars["scope"])
+	delete(r.realm.protocolMappers, r.vars["scope"])
+	for _, scopes := range r.realm.scopeAssignments {
+		delete(scopes, r.vars["scope"])
+	}
+	noContent(w)
+}
@@ -0,0 +1,290 @@
+package keycloaktest
+
+import (
+	"net/http"
+	"strings"
+)
+
+func (s *Server) registerClients() {
+	base := "admin/realms/{realm}/clients"
+	s.handle(http.MethodGet, base, s.listClients)
+	s.handle(http.MethodPost, base, s.postClient)
+	s.handle(http.MethodGet, base+"/{client}", s.getClient)
+	s.handle(http.MethodPut, base+"/{client}", s.updateClient)
+	s.handle(http.MethodDelete, base+"/{client}", s.deleteClient)
+	s.handle(http.MethodGet, base+"/{client}/client-secret", s.getClientSecret)
+	s.handle(http.MethodPost, base+"/{client}/client-secret", s.regenerateClientSecret)
+	s.handle(http.MethodGet, base+"/{client}/service-account-user", s.getServiceAccountUser)
+	s.handle(http.MethodGet, base+"/{client}/roles", s.listClientRoles)
+	s.handle(http.MethodPost, base+"/{client}/roles", s.postClientRole)
+	s.handle(http.MethodGet, base+"/{client}/roles/{role}", s.getClientRole)
+	s.handle(http.MethodPut, base+"/{client}/roles/{role}", s.updateClientRole)
+	s.handle(http.MethodDelete, base+"/{client}/roles/{role}", s.deleteClientRole)
+	s.handle(http.MethodGet, base+"/{client}/roles/{role}/users", s.listClientRoleUsers)
+}
+
+// addClient stores a new client together with its secret, service account
+// and authorization settings.
+func (s *Server) addClient(r *realm, client object) string {
+	for _, key := range []string{"publicClient", "bearerOnly", "serviceAccountsEnabled", "authorizationServicesEnabled"} {
+		if _, ok := client[key]; !ok {
+			client[key] = false
+		}
+	}
+	if _, ok := client["protocol"]; !ok {
+		client["protocol"] = "openid-connect"
+	}
+	secret := str(client, "secret")
+	delete(client, "secret")
+	id := r.clients.add(client)
+	r.clientRoles[id] = newCollection("id")
+
+	if !boolean(client, "publicClient") {
+		if secret == "" {
+			secret = newID()
+		}
+		r.secrets[id] = secret
+	}
+	for scopeID, kind := range r.scopeAssignments[str(r.rep, "id")] {
+		if str(r.clientScopes.get(scopeID), "protocol") == str(client, "protocol") {
+			r.assignScope(id, scopeID, kind)
+		}
+	}
+	s.clientChanged(r, client)
+	return id
+}
+
+// clientChanged creates the service account user and the authorization
+// settings once they are enabled.
+func (s *Server) clientChanged(r *realm, client object) {
+	id := str(client, "id")
+
+	if boolean(client, "authorizationServicesEnabled") {
+		client["serviceAccountsEnabled"] = true
+		if r.resourceServers[id] == nil {
+			r.resourceServers[id] = newResourceServer(client)
+			s.addClientRole(r, id, object{"name": "uma_protection"})
+		}
+	}
+
+	if boolean(client, "serviceAccountsEnabled") {
+		if r.users.find("serviceAccountClientId", id) == nil {
+			s.addUser(r, object{
+				"username":               "service-account-" + str(client, "clientId"),
+				"enabled":                true,
+				"serviceAccountClientId": id,
+			})
+		}
+	}
+}
+
+// addClientRole stores a new role of the client with the given ID.
+func (s *Server) addClientRole(r *realm, id string, role object) {
+	role["clientRole"] = true
+	role["containerId"] = id
+	role["composite"] = false
+	r.clientRoles[id].add(role)
+}
+
+// client returns the client with the path variable "client" or writes a 404
+// error.
+func (s *Server) client(w http.ResponseWriter, r *request) object {
+	client := r.realm.clients.get(r.vars["client"])
+	if client == nil {
+		writeError(w, http.StatusNotFound, "Could not find client")
+	}
+	return client
+}
+
+func (s *Server) listClients(w http.ResponseWriter, r *request) {
+	q := r.URL.Query()
+	clientID := q.Get("clientId")
+	search := q.Get("search") == "true"
+
+	clients := r.realm.clients.filter(func(client object) bool {
+		if clientID == "" {
+			return true
+		}
+		if search {
+			return strings.Contains(strings.ToLower(str(client, "clientId")), strings.ToLower(clientID))
+		}
+		return str(client, "clientId") == clientID
+	})
+	writeJSON(w, http.StatusOK, paginate(clients, r))
+}
+
+func (s *Server) postClient(w http.ResponseWriter, r *request) {
+	var client object
+	if !decode(w, r, &client) {
+		return
+	}
+
+	clientID := str(client, "clientId")
+	if clientID == "" {
+		writeError(w, http.StatusBadRequest, "Client id is missing")
+		return
+	}
+	if r.realm.clients.find("clientId", clientID) != nil {
+		writeConflict(w, "Client "+clientID+" already exists")
+		return
+	}
+	if id := str(client, "id"); id != "" && r.realm.clients.get(id) != nil {
+		writeConflict(w, "Client "+clientID+" already exists")
+		return
+	}
+
+	id := s.addClient(r.realm, client)
+	created(w, s.location("admin", "realms", r.realm.name(), "clients", id))
+}
+
+func (s *Server) getClient(w http.ResponseWriter, r *request) {
+	if client := s.client(w, r); client != nil {
+		writeJSON(w, http.StatusOK, client)
+	}
+}
+
+func (s *Server) updateClient(w http.ResponseWriter, r *request) {
+	client := s.client(w, r)
+	if client == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	if clientID := str(update, "clientId"); clientID != "" {
+		if other := r.realm.clients.find("clientId", clientID); other != nil && str(other, "id") != str(client, "id") {
+			writeConflict(w, "Client "+clientID+" already exists")
+			return
+		}
+	}
+
+	if secret := str(update, "secret"); secret != "" {
+		r.realm.secrets[str(client, "id")] = secret
+	}
+	delete(update, "secret")
+	merge(client, update, "id")
+	if !boolean(client, "publicClient") && r.realm.secrets[str(client, "id")] == "" {
+		r.realm.secrets[str(client, "id")] = newID()
+	}
+	s.clientChanged(r.realm, client)
+	noContent(w)
+}
+
+func (s *Server) deleteClient(w http.ResponseWriter, r *request) {
+	client := s.client(w, r)
+	if client == nil {
+		return
+	}
+
+	id := str(client, "id")
+	for _, role := range r.realm.clientRoles[id].items {
+		r.realm.unmapRole(str(role, "id"))
+	}
+	if user := r.realm.users.find("serviceAccountClientId", id); user != nil {
+		r.realm.users.remove(str(user, "id"))
+	}
+	r.realm.clients.remove(id)
+	delete(r.realm.clientRoles, id)
+	delete(r.realm.secrets, id)
+	delete(r.realm.resourceServers, id)
+	delete(r.realm.protocolMappers, id)
+	delete(r.realm.scopeAssignments, id)
+	noContent(w)
+}
+
+func (s *Server) getClientSecret(w http.ResponseWriter, r *request) {
+	if s.client(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, object{"type": "secret", "value": r.realm.secrets[r.vars["client"]]})
+}
+
+func (s *Server) regenerateClientSecret(w http.ResponseWriter, r *request) {
+	if s.client(w, r) == nil {
+		return
+	}
+	r.realm.secrets[r.vars["client"]] = newID()
+	writeJSON(w, http.StatusOK, object{"type": "secret", "value": r.realm.secrets[r.vars["client"]]})
+}
+
+func (s *Server) getServiceAccountUser(w http.ResponseWriter, r *request) {
+	client := s.client(w, r)
+	if client == nil {
+		return
+	}
+	user := r.realm.users.find("serviceAccountClientId", r.vars["client"])
+	if user == nil || !boolean(client, "serviceAccountsEnabled") {
+		writeError(w, http.StatusBadRequest, "Service account not enabled for the client")
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) listClientRoles(w http.ResponseWriter, r *request) {
+	roles, ok := s.clientRolesOf(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(filterRoles(roles, r), r))
+}
+
+func (s *Server) postClientRole(w http.ResponseWriter, r *request) {
+	roles, ok := s.clientRolesOf(w, r)
+	if !ok {
+		return
+	}
+
+	var role object
+	if !decode(w, r, &role) {
+		return
+	}
+	if !addRole(w, roles, role, r.vars["client"], true) {
+		return
+	}
+	created(w, s.location("admin", "realms", r.realm.name(), "clients", r.vars["client"], "roles", str(role, "name")))
+}
+
+// clientRole returns the client role with the path variable "role" or writes
+// a 404 error.
+func (s *Server) clientRole(w http.ResponseWriter, r *request) object {
+	roles, ok := s.clientRolesOf(w, r)
+	if !ok {
+		return nil
+	}
+	role := roles.find("name", r.vars["role"])
+	if role == nil {
+		writeError(w, http.StatusNotFound, "Could not find role")
+	}
+	return role
+}
+
+func (s *Server) getClientRole(w http.ResponseWriter, r *request) {
+	if role := s.clientRole(w, r); role != nil {
+		writeJSON(w, http.StatusOK, role)
+	}
+}
+
+func (s *Server) updateClientRole(w http.ResponseWriter, r *request) {
+	if role := s.clientRole(w, r); role != nil {
+		s.updateRole(w, r, role)
+	}
+}
+
+func (s *Server) deleteClientRole(w http.ResponseWriter, r *request) {
+	if role := s.clientRole(w, r); role != nil {
+		s.deleteRole(w, r, role)
+	}
+}
+
+func (s *Server) listClientRoleUsers(w http.ResponseWriter, r *request) {
+	role := s.clientRole(w, r)
+	if role == nil {
+		return
+	}
+	users := r.realm.users.filter(func(user object) bool {
+		return r.realm.roleMappings[str(user, "id")][str(role, "id")]
+	})
+	writeJSON(w, http.StatusOK, paginate(users, r))
+}
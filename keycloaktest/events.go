@@ -0,0 +1,353 @@
+package keycloaktest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) registerEvents() {
+	s.handle(http.MethodGet, "admin/realms/{realm}/events", s.listEvents)
+	s.handle(http.MethodDelete, "admin/realms/{realm}/events", s.clearEvents)
+	s.handle(http.MethodGet, "admin/realms/{realm}/events/config", s.getEventsConfig)
+	s.handle(http.MethodPut, "admin/realms/{realm}/events/config", s.updateEventsConfig)
+	s.handle(http.MethodGet, "admin/realms/{realm}/admin-events", s.listAdminEvents)
+	s.handle(http.MethodDelete, "admin/realms/{realm}/admin-events", s.clearAdminEvents)
+}
+
+// AddEvent stores a user event in the realm as if it had been caused by a
+// login. The event is stored even if events are disabled for the realm. The
+// "time" and "realmId" fields are set unless present. Like older Keycloak
+// versions, the event has no "id" unless event contains one.
+func (s *Server) AddEvent(realm string, event map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.state[realm]
+	if r == nil {
+		return fmt.Errorf("keycloaktest: realm %q not found", realm)
+	}
+	r.storeEvent(copyObject(event))
+	return nil
+}
+
+// storeEvent stores the user event e.
+func (r *realm) storeEvent(e object) {
+	if _, ok := e["time"]; !ok {
+		e["time"] = now()
+	}
+	if str(e, "realmId") == "" {
+		e["realmId"] = str(r.rep, "id")
+	}
+	r.events = append(r.events, e)
+}
+
+// recordEvent stores the user event e if events of its type are enabled for
+// the realm.
+func (r *realm) recordEvent(e object) {
+	if !boolean(r.rep, "eventsEnabled") {
+		return
+	}
+	if types, ok := r.rep["enabledEventTypes"].([]interface{}); ok && len(types) > 0 && !containsValue(types, str(e, "type")) {
+		return
+	}
+	e["id"] = newID()
+	r.storeEvent(e)
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// serveAdminChange runs h for a request which changes the realm and records
+// an admin event if it succeeds and admin events are enabled for the realm.
+func (s *Server) serveAdminChange(w http.ResponseWriter, r *request, h handler) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	h(rec, r)
+	if rec.status/100 != 2 || !boolean(r.realm.rep, "adminEventsEnabled") {
+		return
+	}
+
+	prefix := "admin/realms/" + r.vars["realm"]
+	path := strings.TrimPrefix(strings.TrimPrefix(strings.Trim(r.URL.Path, "/"), prefix), "/")
+	if path == "events" || path == "events/config" || path == "admin-events" {
+		return
+	}
+
+	operation := "ACTION"
+	switch r.Method {
+	case http.MethodPost:
+		if location := rec.Header().Get("Location"); rec.status == http.StatusCreated && location != "" {
+			operation = "CREATE"
+			path = strings.TrimPrefix(location, s.URL+prefix+"/")
+		}
+	case http.MethodPut:
+		operation = "UPDATE"
+	case http.MethodDelete:
+		operation = "DELETE"
+	}
+
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	e := object{
+		"id":            newID(),
+		"time":          now(),
+		"realmId":       str(r.realm.rep, "id"),
+		"operationType": operation,
+		"resourceType":  adminResourceType(path),
+		"resourcePath":  path,
+		"authDetails": object{
+			"realmId":   "master",
+			"clientId":  "admin-cli",
+			"ipAddress": ip,
+		},
+	}
+	if boolean(r.realm.rep, "adminEventsDetailsEnabled") && operation != "DELETE" && len(body) > 0 {
+		e["representation"] = string(body)
+	}
+	r.realm.adminEvents = append(r.realm.adminEvents, e)
+}
+
+// adminResourceType returns the type of the resource at path, which is
+// relative to the realm.
+func adminResourceType(path string) string {
+	segments := strings.Split(path, "/")
+	has := func(segment string) bool {
+		for _, s := range segments {
+			if s == segment {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case has("role-mappings") && has("clients"):
+		return "CLIENT_ROLE_MAPPING"
+	case has("role-mappings"):
+		return "REALM_ROLE_MAPPING"
+	case segments[0] == "users" && has("groups"):
+		return "GROUP_MEMBERSHIP"
+	case has("protocol-mappers"):
+		return "PROTOCOL_MAPPER"
+	case segments[0] == "identity-provider" && has("mappers"):
+		return "IDENTITY_PROVIDER_MAPPER"
+	case segments[0] == "clients" && has("authz"):
+		return "AUTHORIZATION_RESOURCE_SERVER"
+	case segments[0] == "clients" && has("roles"):
+		return "CLIENT_ROLE"
+	}
+
+	types := map[string]string{
+		"users":             "USER",
+		"groups":            "GROUP",
+		"roles":             "REALM_ROLE",
+		"roles-by-id":       "REALM_ROLE",
+		"clients":           "CLIENT",
+		"client-scopes":     "CLIENT_SCOPE",
+		"identity-provider": "IDENTITY_PROVIDER",
+		"attack-detection":  "USER_LOGIN_FAILURE",
+	}
+	if t, ok := types[segments[0]]; ok {
+		return t
+	}
+	return "REALM"
+}
+
+// newestFirst returns the events matching keep ordered by time, newest
+// first.
+func newestFirst(events []object, keep func(e object) bool) []object {
+	result := []object{}
+	for i := len(events) - 1; i >= 0; i-- {
+		if keep(events[i]) {
+			result = append(result, copyObject(events[i]))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return eventTime(result[i]) > eventTime(result[j])
+	})
+	return result
+}
+
+// eventTime returns the time of the event e in milliseconds.
+func eventTime(e object) int64 {
+	switch t := e["time"].(type) {
+	case int64:
+		return t
+	case int:
+		return int64(t)
+	case float64:
+		return int64(t)
+	}
+	return 0
+}
+
+// dateRange returns the time range of the "dateFrom" and "dateTo" query
+// parameters in milliseconds. Both accept a day like "2006-01-02" or
+// milliseconds since the epoch.
+func dateRange(r *request) (from, to int64, err error) {
+	parse := func(v string, end bool) (int64, error) {
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return ms, nil
+		}
+		day, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return 0, err
+		}
+		if end {
+			day = day.AddDate(0, 0, 1).Add(-time.Millisecond)
+		}
+		return day.UnixNano() / int64(time.Millisecond), nil
+	}
+
+	from, to = 0, int64(1<<63-1)
+	q := r.URL.Query()
+	if v := q.Get("dateFrom"); v != "" {
+		if from, err = parse(v, false); err != nil {
+			return 0, 0, err
+		}
+	}
+	if v := q.Get("dateTo"); v != "" {
+		if to, err = parse(v, true); err != nil {
+			return 0, 0, err
+		}
+	}
+	return from, to, nil
+}
+
+// matches reports whether want is empty or contains v.
+func matches(want []string, v string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(values []interface{}, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcard returns a regular expression for pattern in which "*" matches
+// any string.
+func wildcard(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *request) {
+	from, to, err := dateRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid value for 'Date(From|To)', expected format is yyyy-MM-dd")
+		return
+	}
+
+	q := r.URL.Query()
+	events := newestFirst(r.realm.events, func(e object) bool {
+		return matches(q["type"], str(e, "type")) &&
+			(q.Get("client") == "" || str(e, "clientId") == q.Get("client")) &&
+			(q.Get("user") == "" || str(e, "userId") == q.Get("user")) &&
+			(q.Get("ipAddress") == "" || str(e, "ipAddress") == q.Get("ipAddress")) &&
+			from <= eventTime(e) && eventTime(e) <= to
+	})
+	writeJSON(w, http.StatusOK, paginate(events, r))
+}
+
+func (s *Server) clearEvents(w http.ResponseWriter, r *request) {
+	r.realm.events = nil
+	noContent(w)
+}
+
+func (s *Server) listAdminEvents(w http.ResponseWriter, r *request) {
+	from, to, err := dateRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid value for 'Date(From|To)', expected format is yyyy-MM-dd")
+		return
+	}
+
+	q := r.URL.Query()
+	var resourcePath *regexp.Regexp
+	if v := q.Get("resourcePath"); v != "" {
+		resourcePath = wildcard(v)
+	}
+	events := newestFirst(r.realm.adminEvents, func(e object) bool {
+		auth, _ := e["authDetails"].(object)
+		return matches(q["operationTypes"], str(e, "operationType")) &&
+			matches(q["resourceTypes"], str(e, "resourceType")) &&
+			(resourcePath == nil || resourcePath.MatchString(str(e, "resourcePath"))) &&
+			(q.Get("authRealm") == "" || str(auth, "realmId") == q.Get("authRealm")) &&
+			(q.Get("authClient") == "" || str(auth, "clientId") == q.Get("authClient")) &&
+			(q.Get("authUser") == "" || str(auth, "userId") == q.Get("authUser")) &&
+			(q.Get("authIpAddress") == "" || str(auth, "ipAddress") == q.Get("authIpAddress")) &&
+			from <= eventTime(e) && eventTime(e) <= to
+	})
+	writeJSON(w, http.StatusOK, paginate(events, r))
+}
+
+func (s *Server) clearAdminEvents(w http.ResponseWriter, r *request) {
+	r.realm.adminEvents = nil
+	noContent(w)
+}
+
+// eventsConfigKeys are the realm fields of the events configuration.
+var eventsConfigKeys = []string{"eventsEnabled", "eventsExpiration", "eventsListeners", "enabledEventTypes", "adminEventsEnabled", "adminEventsDetailsEnabled"}
+
+func (s *Server) getEventsConfig(w http.ResponseWriter, r *request) {
+	config := object{
+		"eventsEnabled":             false,
+		"eventsListeners":           []string{"jboss-logging"},
+		"enabledEventTypes":         []string{},
+		"adminEventsEnabled":        false,
+		"adminEventsDetailsEnabled": false,
+	}
+	for _, key := range eventsConfigKeys {
+		if v, ok := r.realm.rep[key]; ok {
+			config[key] = v
+		}
+	}
+	writeJSON(w, http.StatusOK, config)
+}
+
+func (s *Server) updateEventsConfig(w http.ResponseWriter, r *request) {
+	var config object
+	if !decode(w, r, &config) {
+		return
+	}
+	for _, key := range eventsConfigKeys {
+		if v, ok := config[key]; ok {
+			r.realm.rep[key] = v
+		}
+	}
+	noContent(w)
+}
@@ -0,0 +1,103 @@
+package keycloaktest
+
+import (
+	"net/http"
+	"strings"
+)
+
+func (s *Server) registerGroups() {
+	base := "admin/realms/{realm}/groups"
+	s.handle(http.MethodGet, base, s.listGroups)
+	s.handle(http.MethodPost, base, s.postGroup)
+	s.handle(http.MethodGet, base+"/{id}", s.getGroup)
+	s.handle(http.MethodPut, base+"/{id}", s.updateGroup)
+	s.handle(http.MethodDelete, base+"/{id}", s.deleteGroup)
+	s.registerRoleMappings(base + "/{id}/role-mappings")
+}
+
+// group returns the group with the path variable "id" or writes a 404
+// error.
+func (s *Server) group(w http.ResponseWriter, r *request) object {
+	group := r.realm.groups.get(r.vars["id"])
+	if group == nil {
+		writeError(w, http.StatusNotFound, "Could not find group by id")
+	}
+	return group
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *request) {
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	groups := r.realm.groups.filter(func(group object) bool {
+		return strings.Contains(strings.ToLower(str(group, "name")), search)
+	})
+	writeJSON(w, http.StatusOK, paginate(groups, r))
+}
+
+// addGroup stores a new top-level group.
+func addGroup(r *realm, group object) string {
+	group["path"] = "/" + str(group, "name")
+	group["subGroups"] = []object{}
+	return r.groups.add(group)
+}
+
+func (s *Server) postGroup(w http.ResponseWriter, r *request) {
+	var group object
+	if !decode(w, r, &group) {
+		return
+	}
+	delete(group, "id")
+
+	name := str(group, "name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Group name is missing")
+		return
+	}
+	if r.realm.groups.find("name", name) != nil {
+		writeConflict(w, "Top level group named '"+name+"' already exists.")
+		return
+	}
+
+	id := addGroup(r.realm, group)
+	created(w, s.location("admin", "realms", r.realm.name(), "groups", id))
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *request) {
+	if group := s.group(w, r); group != nil {
+		writeJSON(w, http.StatusOK, group)
+	}
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *request) {
+	group := s.group(w, r)
+	if group == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	if name := str(update, "name"); name != "" {
+		if other := r.realm.groups.find("name", name); other != nil && str(other, "id") != str(group, "id") {
+			writeConflict(w, "Sibling group named '"+name+"' already exists.")
+			return
+		}
+		update["path"] = "/" + name
+	}
+
+	merge(group, update, "id")
+	noContent(w)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *request) {
+	if s.group(w, r) == nil {
+		return
+	}
+	id := r.vars["id"]
+	r.realm.groups.remove(id)
+	delete(r.realm.roleMappings, id)
+	for _, groups := range r.realm.memberships {
+		delete(groups, id)
+	}
+	noContent(w)
+}
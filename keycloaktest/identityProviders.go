@@ -0,0 +1,410 @@
+package keycloaktest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// idpMapperTypes are the mapper types available for every identity
+// provider.
+var idpMapperTypes = []object{
+	{"id": "hardcoded-user-session-attribute-idp-mapper", "name": "Hardcoded User Session Attribute", "category": "Attribute Importer"},
+	{"id": "hardcoded-attribute-idp-mapper", "name": "Hardcoded Attribute", "category": "Attribute Importer"},
+	{"id": "hardcoded-role-idp-mapper", "name": "Hardcoded Role", "category": "Role Importer"},
+	{"id": "oidc-username-idp-mapper", "name": "Username Template Importer", "category": "Preprocessor"},
+}
+
+// oidcMapperTypes are the mapper types available for OpenID Connect
+// identity providers only.
+var oidcMapperTypes = []object{
+	{"id": "oidc-user-attribute-idp-mapper", "name": "Attribute Importer", "category": "Attribute Importer"},
+	{"id": "oidc-role-idp-mapper", "name": "Claim to Role", "category": "Role Importer"},
+}
+
+func (s *Server) registerIdentityProviders() {
+	base := "admin/realms/{realm}/identity-provider"
+	s.handleUnlocked(http.MethodPost, base+"/import-config", s.importIdentityProviderConfig)
+	s.handle(http.MethodGet, base+"/instances", s.listIdentityProviders)
+	s.handle(http.MethodPost, base+"/instances", s.postIdentityProvider)
+	s.handle(http.MethodGet, base+"/instances/{alias}", s.getIdentityProvider)
+	s.handle(http.MethodPut, base+"/instances/{alias}", s.updateIdentityProvider)
+	s.handle(http.MethodDelete, base+"/instances/{alias}", s.deleteIdentityProvider)
+	s.handle(http.MethodGet, base+"/instances/{alias}/mapper-types", s.listIdentityProviderMapperTypes)
+	s.handle(http.MethodGet, base+"/instances/{alias}/mappers", s.listIdentityProviderMappers)
+	s.handle(http.MethodPost, base+"/instances/{alias}/mappers", s.postIdentityProviderMapper)
+	s.handle(http.MethodGet, base+"/instances/{alias}/mappers/{id}", s.getIdentityProviderMapper)
+	s.handle(http.MethodPut, base+"/instances/{alias}/mappers/{id}", s.updateIdentityProviderMapper)
+	s.handle(http.MethodDelete, base+"/instances/{alias}/mappers/{id}", s.deleteIdentityProviderMapper)
+	s.handle(http.MethodGet, base+"/instances/{alias}/management/permissions", s.getIdentityProviderPermissions)
+	s.handle(http.MethodPut, base+"/instances/{alias}/management/permissions", s.updateIdentityProviderPermissions)
+}
+
+// addIdentityProvider stores a new identity provider with the defaults of
+// Keycloak.
+func addIdentityProvider(r *realm, idp object) string {
+	idp["internalId"] = newID()
+	for _, key := range []string{"enabled", "trustEmail", "storeToken", "addReadTokenRoleOnCreate", "authenticateByDefault", "linkOnly"} {
+		if _, ok := idp[key]; !ok {
+			idp[key] = false
+		}
+	}
+	if _, ok := idp["config"]; !ok {
+		idp["config"] = object{}
+	}
+	if _, ok := idp["firstBrokerLoginFlowAlias"]; !ok {
+		idp["firstBrokerLoginFlowAlias"] = "first broker login"
+	}
+	return r.identityProviders.add(idp)
+}
+
+// identityProvider returns the identity provider with the path variable
+// "alias" or writes a 404 error.
+func (s *Server) identityProvider(w http.ResponseWriter, r *request) object {
+	idp := r.realm.identityProviders.get(r.vars["alias"])
+	if idp == nil {
+		writeError(w, http.StatusNotFound, "Could not find identity provider")
+	}
+	return idp
+}
+
+func (s *Server) listIdentityProviders(w http.ResponseWriter, r *request) {
+	search := strings.ToLower(strings.Trim(r.URL.Query().Get("search"), "*"))
+	idps := r.realm.identityProviders.filter(func(idp object) bool {
+		return strings.Contains(strings.ToLower(str(idp, "alias")), search)
+	})
+	writeJSON(w, http.StatusOK, paginate(idps, r))
+}
+
+func (s *Server) postIdentityProvider(w http.ResponseWriter, r *request) {
+	var idp object
+	if !decode(w, r, &idp) {
+		return
+	}
+
+	alias := str(idp, "alias")
+	if alias == "" || str(idp, "providerId") == "" {
+		writeError(w, http.StatusBadRequest, "Invalid identity provider")
+		return
+	}
+	if r.realm.identityProviders.get(alias) != nil {
+		writeConflict(w, "Identity Provider "+alias+" already exists")
+		return
+	}
+
+	addIdentityProvider(r.realm, idp)
+	created(w, s.location("admin", "realms", r.realm.name(), "identity-provider", "instances", alias))
+}
+
+func (s *Server) getIdentityProvider(w http.ResponseWriter, r *request) {
+	if idp := s.identityProvider(w, r); idp != nil {
+		writeJSON(w, http.StatusOK, idp)
+	}
+}
+
+func (s *Server) updateIdentityProvider(w http.ResponseWriter, r *request) {
+	idp := s.identityProvider(w, r)
+	if idp == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	// the identity provider cannot be renamed in the fake
+	delete(update, "internalId")
+	merge(idp, update, "alias")
+	noContent(w)
+}
+
+// deleteIdentityProvider also removes the mappers of the identity provider
+// and the links of all users to it.
+func (s *Server) deleteIdentityProvider(w http.ResponseWriter, r *request) {
+	idp := s.identityProvider(w, r)
+	if idp == nil {
+		return
+	}
+
+	alias := r.vars["alias"]
+	r.realm.identityProviders.remove(alias)
+	delete(r.realm.idpMappers, alias)
+	delete(r.realm.idpPermissions, str(idp, "internalId"))
+	for _, identities := range r.realm.federatedIdentities {
+		identities.remove(alias)
+	}
+	noContent(w)
+}
+
+func (s *Server) listIdentityProviderMapperTypes(w http.ResponseWriter, r *request) {
+	idp := s.identityProvider(w, r)
+	if idp == nil {
+		return
+	}
+
+	types := append([]object{}, idpMapperTypes...)
+	if str(idp, "providerId") == "oidc" || str(idp, "providerId") == "keycloak-oidc" {
+		types = append(types, oidcMapperTypes...)
+	}
+	result := object{}
+	for _, t := range types {
+		t = copyObject(t)
+		t["properties"] = []object{}
+		result[str(t, "id")] = t
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// idpMappersOf returns the mappers of the identity provider with the path
+// variable "alias" or writes a 404 error.
+func (s *Server) idpMappersOf(w http.ResponseWriter, r *request) *collection {
+	if s.identityProvider(w, r) == nil {
+		return nil
+	}
+
+	alias := r.vars["alias"]
+	if r.realm.idpMappers[alias] == nil {
+		r.realm.idpMappers[alias] = newCollection("id")
+	}
+	return r.realm.idpMappers[alias]
+}
+
+func (s *Server) listIdentityProviderMappers(w http.ResponseWriter, r *request) {
+	if mappers := s.idpMappersOf(w, r); mappers != nil {
+		writeJSON(w, http.StatusOK, mappers.filter(nil))
+	}
+}
+
+func (s *Server) postIdentityProviderMapper(w http.ResponseWriter, r *request) {
+	mappers := s.idpMappersOf(w, r)
+	if mappers == nil {
+		return
+	}
+
+	var mapper object
+	if !decode(w, r, &mapper) {
+		return
+	}
+	delete(mapper, "id")
+	mapper["identityProviderAlias"] = r.vars["alias"]
+	if _, ok := mapper["config"]; !ok {
+		mapper["config"] = object{}
+	}
+
+	id := mappers.add(mapper)
+	created(w, s.location("admin", "realms", r.realm.name(), "identity-provider", "instances", r.vars["alias"], "mappers", id))
+}
+
+// idpMapper returns the mapper with the path variable "id" or writes a 404
+// error.
+func (s *Server) idpMapper(w http.ResponseWriter, r *request) object {
+	mappers := s.idpMappersOf(w, r)
+	if mappers == nil {
+		return nil
+	}
+	mapper := mappers.get(r.vars["id"])
+	if mapper == nil {
+		writeError(w, http.StatusNotFound, "Model not found")
+	}
+	return mapper
+}
+
+func (s *Server) getIdentityProviderMapper(w http.ResponseWriter, r *request) {
+	if mapper := s.idpMapper(w, r); mapper != nil {
+		writeJSON(w, http.StatusOK, mapper)
+	}
+}
+
+func (s *Server) updateIdentityProviderMapper(w http.ResponseWriter, r *request) {
+	mapper := s.idpMapper(w, r)
+	if mapper == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	delete(update, "identityProviderAlias")
+	merge(mapper, update, "id")
+	noContent(w)
+}
+
+func (s *Server) deleteIdentityProviderMapper(w http.ResponseWriter, r *request) {
+	if s.idpMapper(w, r) == nil {
+		return
+	}
+	r.realm.idpMappers[r.vars["alias"]].remove(r.vars["id"])
+	noContent(w)
+}
+
+// idpPermissions returns the management permissions of the identity
+// provider idp.
+func idpPermissions(r *realm, idp object) object {
+	id := str(idp, "internalId")
+	if !r.idpPermissions[id] {
+		return object{"enabled": false}
+	}
+	return object{
+		"enabled":  true,
+		"resource": id,
+		"scopePermissions": object{
+			"token-exchange": "token-exchange.permission.idp." + id,
+		},
+	}
+}
+
+func (s *Server) getIdentityProviderPermissions(w http.ResponseWriter, r *request) {
+	if idp := s.identityProvider(w, r); idp != nil {
+		writeJSON(w, http.StatusOK, idpPermissions(r.realm, idp))
+	}
+}
+
+func (s *Server) updateIdentityProviderPermissions(w http.ResponseWriter, r *request) {
+	idp := s.identityProvider(w, r)
+	if idp == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	if boolean(update, "enabled") {
+		r.realm.idpPermissions[str(idp, "internalId")] = true
+	} else {
+		delete(r.realm.idpPermissions, str(idp, "internalId"))
+	}
+	writeJSON(w, http.StatusOK, idpPermissions(r.realm, idp))
+}
+
+// importIdentityProviderConfig converts an OpenID Connect discovery document
+// into the config of an "oidc" identity provider. The document is either
+// uploaded as "file" of a multipart form or fetched from "fromUrl". Discovery
+// documents of the fake itself are resolved without a request. The handler
+// runs unlocked so other URLs are fetched without holding the server lock.
+func (s *Server) importIdentityProviderConfig(w http.ResponseWriter, r *request) {
+	if !s.realmExists(r.vars["realm"]) {
+		writeError(w, http.StatusNotFound, "Realm not found.")
+		return
+	}
+
+	var providerID, fromURL string
+	var discovery object
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		providerID = r.FormValue("providerId")
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Missing file")
+			return
+		}
+		defer file.Close()
+		if err := json.NewDecoder(file).Decode(&discovery); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid discovery document")
+			return
+		}
+	} else {
+		var body object
+		if !decode(w, r, &body) {
+			return
+		}
+		providerID = str(body, "providerId")
+		fromURL = str(body, "fromUrl")
+		if _, own := s.discoveryRealm(fromURL); !own {
+			var ok bool
+			if discovery, ok = fetchDiscovery(r.Context(), fromURL); !ok {
+				writeError(w, http.StatusBadRequest, "Could not fetch discovery document")
+				return
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the realm may have been deleted meanwhile
+	if s.state[r.vars["realm"]] == nil {
+		writeError(w, http.StatusNotFound, "Realm not found.")
+		return
+	}
+	if discovery == nil {
+		name, _ := s.discoveryRealm(fromURL)
+		target := s.state[name]
+		if target == nil {
+			writeError(w, http.StatusBadRequest, "Could not fetch discovery document")
+			return
+		}
+		discovery = s.openIDConfiguration(target)
+	}
+
+	if providerID != "oidc" && providerID != "keycloak-oidc" {
+		writeError(w, http.StatusBadRequest, "Unsupported provider "+providerID)
+		return
+	}
+
+	config := object{
+		"validateSignature": "true",
+		"useJwksUrl":        "true",
+	}
+	for key, claim := range map[string]string{
+		"issuer":           "issuer",
+		"authorizationUrl": "authorization_endpoint",
+		"tokenUrl":         "token_endpoint",
+		"userInfoUrl":      "userinfo_endpoint",
+		"logoutUrl":        "end_session_endpoint",
+		"jwksUrl":          "jwks_uri",
+	} {
+		if v := str(discovery, claim); v != "" {
+			config[key] = v
+		}
+	}
+	writeJSON(w, http.StatusOK, config)
+}
+
+// discoveryTimeout limits the time to fetch a discovery document.
+const discoveryTimeout = 10 * time.Second
+
+// realmExists reports whether the realm name exists.
+func (s *Server) realmExists(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state[name] != nil
+}
+
+// discoveryRealm returns the name of the realm if u is the URL of a discovery
+// document of the fake itself.
+func (s *Server) discoveryRealm(u string) (string, bool) {
+	const suffix = "/.well-known/openid-configuration"
+	if !strings.HasPrefix(u, s.URL+"realms/") || !strings.HasSuffix(u, suffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(u, s.URL+"realms/"), suffix), true
+}
+
+// fetchDiscovery returns the discovery document at u. It must be called
+// without holding the server lock.
+func fetchDiscovery(ctx context.Context, u string) (object, bool) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, false
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, false
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, false
+	}
+
+	var discovery object
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&discovery); err != nil {
+		return nil, false
+	}
+	return discovery, true
+}
@@ -0,0 +1,272 @@
+package keycloaktest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+func (s *Server) registerPartialImport() {
+	s.handle(http.MethodPost, "admin/realms/{realm}/partialImport", s.partialImport)
+	s.handle(http.MethodPost, "admin/realms/{realm}/partial-export", s.partialExport)
+}
+
+// importer imports resources of a single type.
+type importer struct {
+	resourceType string
+	// label names the type in error messages.
+	label   string
+	nameKey string
+	// existing returns the resource with the same name as o.
+	existing func(o object) object
+	// add stores o and returns its ID.
+	add func(o object) string
+	// overwrite replaces the existing resource with o and returns its ID.
+	overwrite func(existing, o object) string
+}
+
+// importBatch holds the resources of a single type of an import.
+type importBatch struct {
+	importer *importer
+	items    []object
+}
+
+func (s *Server) userImporter(r *realm) *importer {
+	return &importer{
+		resourceType: "USER",
+		label:        "User",
+		nameKey:      "username",
+		existing: func(o object) object {
+			return existingUser(r, o)
+		},
+		add: func(o object) string {
+			return s.addUser(r, o)
+		},
+		overwrite: func(existing, o object) string {
+			removeUser(r, str(existing, "id"))
+			return s.addUser(r, o)
+		},
+	}
+}
+
+func (s *Server) clientImporter(r *realm) *importer {
+	return &importer{
+		resourceType: "CLIENT",
+		label:        "Client",
+		nameKey:      "clientId",
+		existing: func(o object) object {
+			return r.clients.find("clientId", str(o, "clientId"))
+		},
+		add: func(o object) string {
+			return s.addClient(r, o)
+		},
+		overwrite: func(existing, o object) string {
+			merge(existing, o, "id")
+			s.clientChanged(r, existing)
+			return str(existing, "id")
+		},
+	}
+}
+
+func identityProviderImporter(r *realm) *importer {
+	return &importer{
+		resourceType: "IDP",
+		label:        "Identity provider",
+		nameKey:      "alias",
+		existing: func(o object) object {
+			return r.identityProviders.get(str(o, "alias"))
+		},
+		add: func(o object) string {
+			addIdentityProvider(r, o)
+			return str(o, "internalId")
+		},
+		overwrite: func(existing, o object) string {
+			delete(o, "internalId")
+			merge(existing, o, "alias")
+			return str(existing, "internalId")
+		},
+	}
+}
+
+func groupImporter(r *realm) *importer {
+	return &importer{
+		resourceType: "GROUP",
+		label:        "Group",
+		nameKey:      "name",
+		existing: func(o object) object {
+			return r.groups.find("name", str(o, "name"))
+		},
+		add: func(o object) string {
+			return addGroup(r, o)
+		},
+		overwrite: func(existing, o object) string {
+			merge(existing, o, "id")
+			return str(existing, "id")
+		},
+	}
+}
+
+// roleImporter imports the realm roles or the roles of the client with
+// clientID.
+func roleImporter(r *realm, clientID string) *importer {
+	// the client may be imported by the same request
+	container := func() (*collection, string) {
+		if clientID == "" {
+			return r.roles, str(r.rep, "id")
+		}
+		id := str(r.clients.find("clientId", clientID), "id")
+		return r.clientRoles[id], id
+	}
+
+	i := &importer{
+		resourceType: "REALM_ROLE",
+		label:        "Realm role",
+		nameKey:      "name",
+		existing: func(o object) object {
+			if roles, _ := container(); roles != nil {
+				return roles.find("name", str(o, "name"))
+			}
+			return nil
+		},
+		add: func(o object) string {
+			roles, id := container()
+			return storeRole(roles, o, id, clientID != "")
+		},
+		overwrite: func(existing, o object) string {
+			delete(o, "containerId")
+			delete(o, "clientRole")
+			merge(existing, o, "id")
+			return str(existing, "id")
+		},
+	}
+	if clientID != "" {
+		i.resourceType = "CLIENT_ROLE"
+		i.label = "Client role"
+	}
+	return i
+}
+
+// partialImport imports clients, realm and client roles, identity providers,
+// groups and users.
+// With the policy "FAIL" nothing is imported if a single resource exists,
+// "SKIP" keeps and "OVERWRITE" replaces existing resources.
+func (s *Server) partialImport(w http.ResponseWriter, r *request) {
+	var body struct {
+		IfResourceExists  string   `json:"ifResourceExists"`
+		Users             []object `json:"users"`
+		Clients           []object `json:"clients"`
+		Groups            []object `json:"groups"`
+		IdentityProviders []object `json:"identityProviders"`
+		Roles             struct {
+			Realm  []object            `json:"realm"`
+			Client map[string][]object `json:"client"`
+		} `json:"roles"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	imported := map[string]bool{}
+	for _, client := range body.Clients {
+		imported[str(client, "clientId")] = true
+	}
+	clientIDs := make([]string, 0, len(body.Roles.Client))
+	for clientID := range body.Roles.Client {
+		if !imported[clientID] && r.realm.clients.find("clientId", clientID) == nil {
+			writeError(w, http.StatusBadRequest, "Client '"+clientID+"' does not exist")
+			return
+		}
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	// the order of Keycloak
+	batches := []importBatch{
+		{s.clientImporter(r.realm), body.Clients},
+		{roleImporter(r.realm, ""), body.Roles.Realm},
+	}
+	for _, clientID := range clientIDs {
+		batches = append(batches, importBatch{roleImporter(r.realm, clientID), body.Roles.Client[clientID]})
+	}
+	batches = append(batches,
+		importBatch{identityProviderImporter(r.realm), body.IdentityProviders},
+		importBatch{groupImporter(r.realm), body.Groups},
+		importBatch{s.userImporter(r.realm), body.Users},
+	)
+
+	if body.IfResourceExists == "FAIL" {
+		for _, batch := range batches {
+			for _, o := range batch.items {
+				if batch.importer.existing(o) != nil {
+					writeConflict(w, batch.importer.label+" '"+str(o, batch.importer.nameKey)+"' already exists.")
+					return
+				}
+			}
+		}
+	}
+
+	results := []object{}
+	counts := map[string]int{}
+	for _, batch := range batches {
+		i := batch.importer
+		for _, o := range batch.items {
+			delete(o, "id")
+			action, id := "ADDED", ""
+			if existing := i.existing(o); existing == nil {
+				id = i.add(o)
+			} else if body.IfResourceExists == "SKIP" {
+				action, id = "SKIPPED", str(existing, "id")
+			} else {
+				action, id = "OVERWRITTEN", i.overwrite(existing, o)
+			}
+			counts[action]++
+			results = append(results, object{
+				"action":       action,
+				"resourceType": i.resourceType,
+				"resourceName": str(o, i.nameKey),
+				"id":           id,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, object{
+		"added":       counts["ADDED"],
+		"skipped":     counts["SKIPPED"],
+		"overwritten": counts["OVERWRITTEN"],
+		"results":     results,
+	})
+}
+
+// partialExport exports the realm with its clients if "exportClients" is
+// true and its groups and roles if "exportGroupsAndRoles" is true. Client
+// secrets are masked like in Keycloak.
+func (s *Server) partialExport(w http.ResponseWriter, r *request) {
+	q := r.URL.Query()
+	realm := copyObject(r.realm.rep)
+
+	if ok, _ := strconv.ParseBool(q.Get("exportClients")); ok {
+		clients := r.realm.clients.filter(nil)
+		for _, client := range clients {
+			if r.realm.secrets[str(client, "id")] != "" {
+				client["secret"] = "**********"
+			}
+		}
+		realm["clients"] = clients
+	}
+
+	if ok, _ := strconv.ParseBool(q.Get("exportGroupsAndRoles")); ok {
+		realm["groups"] = r.realm.groups.filter(nil)
+		clientRoles := object{}
+		for _, client := range r.realm.clients.items {
+			if roles := r.realm.clientRoles[str(client, "id")]; roles != nil && len(roles.items) > 0 {
+				clientRoles[str(client, "clientId")] = roles.filter(nil)
+			}
+		}
+		realm["roles"] = object{
+			"realm":  r.realm.roles.filter(nil),
+			"client": clientRoles,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, realm)
+}
@@ -0,0 +1,136 @@
+package keycloaktest
+
+import (
+	"net/http"
+	"strings"
+)
+
+func (s *Server) registerProtocolMappers() {
+	for _, base := range []string{"admin/realms/{realm}/clients/{owner}", "admin/realms/{realm}/client-scopes/{owner}"} {
+		s.handle(http.MethodGet, base+"/protocol-mappers/models", s.listProtocolMappers)
+		s.handle(http.MethodPost, base+"/protocol-mappers/models", s.postProtocolMapper)
+		s.handle(http.MethodPost, base+"/protocol-mappers/add-models", s.addProtocolMappers)
+		s.handle(http.MethodGet, base+"/protocol-mappers/models/{id}", s.getProtocolMapper)
+		s.handle(http.MethodPut, base+"/protocol-mappers/models/{id}", s.updateProtocolMapper)
+		s.handle(http.MethodDelete, base+"/protocol-mappers/models/{id}", s.deleteProtocolMapper)
+	}
+}
+
+// protocolMappersOf returns the protocol mappers of the client or client
+// scope with the path variable "owner" or writes a 404 error.
+func (s *Server) protocolMappersOf(w http.ResponseWriter, r *request) *collection {
+	owner := r.vars["owner"]
+	// admin/realms/{realm}/clients/{owner}/...
+	if strings.Split(strings.Trim(r.URL.Path, "/"), "/")[3] == "clients" {
+		if r.realm.clients.get(owner) == nil {
+			writeError(w, http.StatusNotFound, "Could not find client")
+			return nil
+		}
+	} else if r.realm.clientScopes.get(owner) == nil {
+		writeError(w, http.StatusNotFound, "Could not find client scope")
+		return nil
+	}
+
+	mappers := r.realm.protocolMappers[owner]
+	if mappers == nil {
+		mappers = newCollection("id")
+		r.realm.protocolMappers[owner] = mappers
+	}
+	return mappers
+}
+
+// addProtocolMapper validates and stores a new protocol mapper. It returns
+// false if a mapper with the same name exists.
+func addProtocolMapper(w http.ResponseWriter, mappers *collection, mapper object) bool {
+	delete(mapper, "id")
+	if mappers.find("name", str(mapper, "name")) != nil {
+		writeConflict(w, "Protocol mapper exists with same name")
+		return false
+	}
+	mappers.add(mapper)
+	return true
+}
+
+func (s *Server) listProtocolMappers(w http.ResponseWriter, r *request) {
+	if mappers := s.protocolMappersOf(w, r); mappers != nil {
+		writeJSON(w, http.StatusOK, mappers.filter(nil))
+	}
+}
+
+func (s *Server) postProtocolMapper(w http.ResponseWriter, r *request) {
+	mappers := s.protocolMappersOf(w, r)
+	if mappers == nil {
+		return
+	}
+
+	var mapper object
+	if !decode(w, r, &mapper) {
+		return
+	}
+	if !addProtocolMapper(w, mappers, mapper) {
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	created(w, s.location(append(segments, str(mapper, "id"))...))
+}
+
+func (s *Server) addProtocolMappers(w http.ResponseWriter, r *request) {
+	mappers := s.protocolMappersOf(w, r)
+	if mappers == nil {
+		return
+	}
+
+	var reps []object
+	if !decode(w, r, &reps) {
+		return
+	}
+	for _, mapper := range reps {
+		if !addProtocolMapper(w, mappers, mapper) {
+			return
+		}
+	}
+	noContent(w)
+}
+
+// protocolMapper returns the protocol mapper with the path variable "id" or
+// writes a 404 error.
+func (s *Server) protocolMapper(w http.ResponseWriter, r *request) object {
+	mappers := s.protocolMappersOf(w, r)
+	if mappers == nil {
+		return nil
+	}
+	mapper := mappers.get(r.vars["id"])
+	if mapper == nil {
+		writeError(w, http.StatusNotFound, "Model not found")
+	}
+	return mapper
+}
+
+func (s *Server) getProtocolMapper(w http.ResponseWriter, r *request) {
+	if mapper := s.protocolMapper(w, r); mapper != nil {
+		writeJSON(w, http.StatusOK, mapper)
+	}
+}
+
+func (s *Server) updateProtocolMapper(w http.ResponseWriter, r *request) {
+	mapper := s.protocolMapper(w, r)
+	if mapper == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	merge(mapper, update, "id")
+	noContent(w)
+}
+
+func (s *Server) deleteProtocolMapper(w http.ResponseWriter, r *request) {
+	if s.protocolMapper(w, r) == nil {
+		return
+	}
+	r.realm.protocolMappers[r.vars["owner"]].remove(r.vars["id"])
+	noContent(w)
+}
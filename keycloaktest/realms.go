@@ -0,0 +1,214 @@
+package keycloaktest
+
+import (
+	"net/http"
+)
+
+// defaultClients are created by Keycloak for every new realm.
+var defaultClients = []string{
+	"account",
+	"account-console",
+	"admin-cli",
+	"broker",
+	"realm-management",
+	"security-admin-console",
+}
+
+// realmManagementRoles are the roles of the "realm-management" client.
+var realmManagementRoles = []string{
+	"create-client",
+	"impersonation",
+	"manage-authorization",
+	"manage-clients",
+	"manage-events",
+	"manage-identity-providers",
+	"manage-realm",
+	"manage-users",
+	"query-clients",
+	"query-groups",
+	"query-realms",
+	"query-users",
+	"realm-admin",
+	"view-authorization",
+	"view-clients",
+	"view-events",
+	"view-identity-providers",
+	"view-realm",
+	"view-users",
+}
+
+// defaultClientScopes are created by Keycloak for every new realm.
+var defaultClientScopes = map[string]string{
+	"acr":              "openid-connect",
+	"address":          "openid-connect",
+	"email":            "openid-connect",
+	"microprofile-jwt": "openid-connect",
+	"offline_access":   "openid-connect",
+	"phone":            "openid-connect",
+	"profile":          "openid-connect",
+	"role_list":        "saml",
+	"roles":            "openid-connect",
+	"web-origins":      "openid-connect",
+}
+
+// optionalClientScopes are the realm default client scopes which are
+// optional instead of default.
+var optionalClientScopes = map[string]bool{
+	"address":          true,
+	"microprofile-jwt": true,
+	"offline_access":   true,
+	"phone":            true,
+}
+
+func (s *Server) registerRealms() {
+	s.handle(http.MethodGet, "admin/realms", s.listRealms)
+	s.handle(http.MethodPost, "admin/realms", s.postRealm)
+	s.handle(http.MethodGet, "admin/realms/{realm}", s.getRealm)
+	s.handle(http.MethodPut, "admin/realms/{realm}", s.updateRealm)
+	s.handle(http.MethodDelete, "admin/realms/{realm}", s.deleteRealm)
+	s.handle(http.MethodDelete, "admin/realms/{realm}/sessions/{session}", s.deleteSession)
+	s.handle(http.MethodPost, "admin/realms/{realm}/logout-all", s.logoutAll)
+	s.handle(http.MethodGet, "realms/{realm}/.well-known/openid-configuration", s.getOpenIDConfiguration)
+	s.handle(http.MethodGet, "realms/{realm}/.well-known/uma2-configuration", s.getUMAConfiguration)
+}
+
+// createRealm adds a new realm with the default clients, roles and client
+// scopes.
+func (s *Server) createRealm(rep object) *realm {
+	name := str(rep, "realm")
+	if str(rep, "id") == "" {
+		rep["id"] = name
+	}
+	s.realms.add(rep)
+
+	r := newRealm(rep)
+	s.state[name] = r
+
+	defaultRoles := object{"name": "default-roles-" + name, "composite": true}
+	for _, role := range []object{defaultRoles, {"name": "offline_access"}, {"name": "uma_authorization"}} {
+		role["clientRole"] = false
+		role["containerId"] = str(rep, "id")
+		if _, ok := role["composite"]; !ok {
+			role["composite"] = false
+		}
+		r.roles.add(role)
+	}
+	rep["defaultRole"] = copyObject(defaultRoles)
+	for _, name := range []string{"offline_access", "uma_authorization"} {
+		r.addComposite(str(defaultRoles, "id"), str(r.roles.find("name", name), "id"))
+	}
+
+	// client scopes come before the clients which get the realm default
+	// scopes assigned
+	for _, name := range sortedKeys(defaultClientScopes) {
+		id := r.clientScopes.add(object{"name": name, "protocol": defaultClientScopes[name]})
+		kind := "default"
+		if optionalClientScopes[name] {
+			kind = "optional"
+		}
+		r.assignScope(str(rep, "id"), id, kind)
+	}
+
+	for _, clientID := range defaultClients {
+		client := object{
+			"clientId":     clientID,
+			"enabled":      true,
+			"publicClient": clientID == "admin-cli" || clientID == "account-console" || clientID == "security-admin-console",
+			"bearerOnly":   clientID == "broker" || clientID == "realm-management",
+			"protocol":     "openid-connect",
+		}
+		s.addClient(r, client)
+	}
+
+	management := r.clients.find("clientId", "realm-management")
+	for _, name := range realmManagementRoles {
+		s.addClientRole(r, str(management, "id"), object{"name": name})
+	}
+
+	return r
+}
+
+func (s *Server) listRealms(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, s.realms.filter(nil))
+}
+
+func (s *Server) postRealm(w http.ResponseWriter, r *request) {
+	var rep object
+	if !decode(w, r, &rep) {
+		return
+	}
+
+	name := str(rep, "realm")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Realm name cannot be empty")
+		return
+	}
+	if s.state[name] != nil || (str(rep, "id") != "" && s.realms.get(str(rep, "id")) != nil) {
+		writeConflict(w, "Conflict detected. See logs for details")
+		return
+	}
+
+	s.createRealm(rep)
+	created(w, s.location("admin", "realms", name))
+}
+
+func (s *Server) getRealm(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, r.realm.rep)
+}
+
+func (s *Server) updateRealm(w http.ResponseWriter, r *request) {
+	var rep object
+	if !decode(w, r, &rep) {
+		return
+	}
+
+	// the realm cannot be renamed in the fake
+	delete(rep, "realm")
+	merge(r.realm.rep, rep, "id")
+	noContent(w)
+}
+
+func (s *Server) deleteRealm(w http.ResponseWriter, r *request) {
+	s.realms.remove(str(r.realm.rep, "id"))
+	delete(s.state, r.realm.name())
+	noContent(w)
+}
+
+// deleteSession always fails since the fake has no sessions.
+func (s *Server) deleteSession(w http.ResponseWriter, r *request) {
+	writeError(w, http.StatusNotFound, "Session not found")
+}
+
+func (s *Server) logoutAll(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, object{})
+}
+
+// openIDConfiguration returns the endpoints of the OpenID Connect discovery
+// document of the realm.
+func (s *Server) openIDConfiguration(r *realm) object {
+	issuer := s.URL + "realms/" + r.name()
+	return object{
+		"issuer":                 issuer,
+		"authorization_endpoint": issuer + "/protocol/openid-connect/auth",
+		"token_endpoint":         issuer + "/protocol/openid-connect/token",
+		"introspection_endpoint": issuer + "/protocol/openid-connect/token/introspect",
+		"userinfo_endpoint":      issuer + "/protocol/openid-connect/userinfo",
+		"end_session_endpoint":   issuer + "/protocol/openid-connect/logout",
+		"jwks_uri":               issuer + "/protocol/openid-connect/certs",
+		"registration_endpoint":  issuer + "/clients-registrations/openid-connect",
+	}
+}
+
+func (s *Server) getOpenIDConfiguration(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, s.openIDConfiguration(r.realm))
+}
+
+func (s *Server) getUMAConfiguration(w http.ResponseWriter, r *request) {
+	config := s.openIDConfiguration(r.realm)
+	delete(config, "userinfo_endpoint")
+	issuer := str(config, "issuer")
+	config["resource_registration_endpoint"] = issuer + "/authz/protection/resource_set"
+	config["permission_endpoint"] = issuer + "/authz/protection/permission"
+	config["policy_endpoint"] = issuer + "/authz/protection/uma-policy"
+	writeJSON(w, http.StatusOK, config)
+}
@@ -0,0 +1,371 @@
+package keycloaktest
+
+import (
+	"net/http"
+	"strings"
+)
+
+func (s *Server) registerRoles() {
+	base := "admin/realms/{realm}"
+	s.handle(http.MethodGet, base+"/roles", s.listRealmRoles)
+	s.handle(http.MethodPost, base+"/roles", s.postRealmRole)
+	s.handle(http.MethodGet, base+"/roles/{role}", s.getRealmRole)
+	s.handle(http.MethodPut, base+"/roles/{role}", s.updateRealmRole)
+	s.handle(http.MethodDelete, base+"/roles/{role}", s.deleteRealmRole)
+	s.handle(http.MethodGet, base+"/roles-by-id/{id}", s.getRoleByID)
+	s.handle(http.MethodPut, base+"/roles-by-id/{id}", s.updateRoleByID)
+	s.handle(http.MethodDelete, base+"/roles-by-id/{id}", s.deleteRoleByID)
+}
+
+// filterRoles applies the "search" query parameter.
+func filterRoles(c *collection, r *request) []object {
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	return c.filter(func(role object) bool {
+		return strings.Contains(strings.ToLower(str(role, "name")), search)
+	})
+}
+
+// addRole validates and stores a new realm or client role. It returns
+// false if a role with the same name exists.
+func addRole(w http.ResponseWriter, c *collection, role object, container string, clientRole bool) bool {
+	delete(role, "id")
+	if str(role, "name") == "" {
+		writeError(w, http.StatusBadRequest, "Role name cannot be empty")
+		return false
+	}
+	if c.find("name", str(role, "name")) != nil {
+		writeConflict(w, "Role with name "+str(role, "name")+" already exists")
+		return false
+	}
+
+	storeRole(c, role, container, clientRole)
+	return true
+}
+
+// storeRole stores a new realm or client role without validation.
+func storeRole(c *collection, role object, container string, clientRole bool) string {
+	role["clientRole"] = clientRole
+	role["containerId"] = container
+	if _, ok := role["composite"]; !ok {
+		role["composite"] = false
+	}
+	return c.add(role)
+}
+
+func (s *Server) listRealmRoles(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, paginate(filterRoles(r.realm.roles, r), r))
+}
+
+func (s *Server) postRealmRole(w http.ResponseWriter, r *request) {
+	var role object
+	if !decode(w, r, &role) {
+		return
+	}
+	if !addRole(w, r.realm.roles, role, str(r.realm.rep, "id"), false) {
+		return
+	}
+	created(w, s.location("admin", "realms", r.realm.name(), "roles", str(role, "name")))
+}
+
+// realmRole returns the realm role with the path variable "role" or writes
+// a 404 error.
+func (s *Server) realmRole(w http.ResponseWriter, r *request) object {
+	role := r.realm.roles.find("name", r.vars["role"])
+	if role == nil {
+		writeError(w, http.StatusNotFound, "Could not find role")
+	}
+	return role
+}
+
+func (s *Server) getRealmRole(w http.ResponseWriter, r *request) {
+	if role := s.realmRole(w, r); role != nil {
+		writeJSON(w, http.StatusOK, role)
+	}
+}
+
+func (s *Server) updateRealmRole(w http.ResponseWriter, r *request) {
+	if role := s.realmRole(w, r); role != nil {
+		s.updateRole(w, r, role)
+	}
+}
+
+func (s *Server) deleteRealmRole(w http.ResponseWriter, r *request) {
+	if role := s.realmRole(w, r); role != nil {
+		s.deleteRole(w, r, role)
+	}
+}
+
+// roleByID returns the realm or client role with the path variable "id" or
+// writes a 404 error.
+func (s *Server) roleByID(w http.ResponseWriter, r *request) object {
+	role := r.realm.role(r.vars["id"])
+	if role == nil {
+		writeError(w, http.StatusNotFound, "Could not find role with id")
+	}
+	return role
+}
+
+func (s *Server) getRoleByID(w http.ResponseWriter, r *request) {
+	if role := s.roleByID(w, r); role != nil {
+		writeJSON(w, http.StatusOK, role)
+	}
+}
+
+func (s *Server) updateRoleByID(w http.ResponseWriter, r *request) {
+	if role := s.roleByID(w, r); role != nil {
+		s.updateRole(w, r, role)
+	}
+}
+
+func (s *Server) deleteRoleByID(w http.ResponseWriter, r *request) {
+	if role := s.roleByID(w, r); role != nil {
+		s.deleteRole(w, r, role)
+	}
+}
+
+func (s *Server) updateRole(w http.ResponseWriter, r *request, role object) {
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	delete(update, "clientRole")
+	delete(update, "containerId")
+	merge(role, update, "id")
+	noContent(w)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *request, role object) {
+	id := str(role, "id")
+	if !r.realm.roles.remove(id) {
+		for _, roles := range r.realm.clientRoles {
+			roles.remove(id)
+		}
+	}
+	r.realm.unmapRole(id)
+	noContent(w)
+}
+
+// registerRoleMappings registers the role mapping routes of users or groups
+// below base.
+func (s *Server) registerRoleMappings(base string) {
+	s.handle(http.MethodGet, base, s.getRoleMappings)
+	s.handle(http.MethodGet, base+"/realm", s.listRealmRoleMappings)
+	s.handle(http.MethodPost, base+"/realm", s.addRealmRoleMappings)
+	s.handle(http.MethodDelete, base+"/realm", s.removeRealmRoleMappings)
+	s.handle(http.MethodGet, base+"/realm/composite", s.listEffectiveRoleMappings)
+	s.handle(http.MethodGet, base+"/realm/available", s.listAvailableRoleMappings)
+	s.handle(http.MethodGet, base+"/clients/{client}", s.listClientRoleMappings)
+	s.handle(http.MethodPost, base+"/clients/{client}", s.addClientRoleMappings)
+	s.handle(http.MethodDelete, base+"/clients/{client}", s.removeClientRoleMappings)
+	s.handle(http.MethodGet, base+"/clients/{client}/composite", s.listEffectiveRoleMappings)
+	s.handle(http.MethodGet, base+"/clients/{client}/available", s.listAvailableRoleMappings)
+}
+
+// subject returns the ID of the user or group with the path variable "id"
+// or writes a 404 error.
+func (s *Server) subject(w http.ResponseWriter, r *request) (string, bool) {
+	id := r.vars["id"]
+	// admin/realms/{realm}/groups/{id}/role-mappings/...
+	if strings.Split(strings.Trim(r.URL.Path, "/"), "/")[3] == "groups" {
+		if r.realm.groups.get(id) == nil {
+			writeError(w, http.StatusNotFound, "Could not find group by id")
+			return "", false
+		}
+		return id, true
+	}
+	if r.realm.users.get(id) == nil {
+		writeError(w, http.StatusNotFound, "User not found")
+		return "", false
+	}
+	return id, true
+}
+
+// mappingRoles decodes the roles of a role mapping request and resolves
+// them by ID or name within roles.
+func (s *Server) mappingRoles(w http.ResponseWriter, r *request, roles *collection) ([]object, bool) {
+	var reps []object
+	if !decode(w, r, &reps) {
+		return nil, false
+	}
+
+	var found []object
+	for _, rep := range reps {
+		role := roles.get(str(rep, "id"))
+		if role == nil {
+			role = roles.find("name", str(rep, "name"))
+		}
+		if role == nil {
+			writeError(w, http.StatusNotFound, "Role not found")
+			return nil, false
+		}
+		found = append(found, role)
+	}
+	return found, true
+}
+
+func (s *Server) listRealmRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, r.realm.mappedRoles(id, str(r.realm.rep, "id")))
+}
+
+func (s *Server) addRealmRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	roles, ok := s.mappingRoles(w, r, r.realm.roles)
+	if !ok {
+		return
+	}
+	for _, role := range roles {
+		r.realm.mapRole(id, str(role, "id"))
+	}
+	noContent(w)
+}
+
+func (s *Server) removeRealmRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	roles, ok := s.mappingRoles(w, r, r.realm.roles)
+	if !ok {
+		return
+	}
+	for _, role := range roles {
+		delete(r.realm.roleMappings[id], str(role, "id"))
+	}
+	noContent(w)
+}
+
+// clientRolesOf returns the roles of the client with the path variable
+// "client" or writes a 404 error.
+func (s *Server) clientRolesOf(w http.ResponseWriter, r *request) (*collection, bool) {
+	roles, ok := r.realm.clientRoles[r.vars["client"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Could not find client")
+	}
+	return roles, ok
+}
+
+func (s *Server) listClientRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := s.clientRolesOf(w, r); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, r.realm.mappedRoles(id, r.vars["client"]))
+}
+
+func (s *Server) addClientRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	clientRoles, ok := s.clientRolesOf(w, r)
+	if !ok {
+		return
+	}
+	roles, ok := s.mappingRoles(w, r, clientRoles)
+	if !ok {
+		return
+	}
+	for _, role := range roles {
+		r.realm.mapRole(id, str(role, "id"))
+	}
+	noContent(w)
+}
+
+func (s *Server) removeClientRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	clientRoles, ok := s.clientRolesOf(w, r)
+	if !ok {
+		return
+	}
+	roles, ok := s.mappingRoles(w, r, clientRoles)
+	if !ok {
+		return
+	}
+	for _, role := range roles {
+		delete(r.realm.roleMappings[id], str(role, "id"))
+	}
+	noContent(w)
+}
+
+func (s *Server) getRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+
+	mappings := object{}
+	if roles := r.realm.mappedRoles(id, str(r.realm.rep, "id")); len(roles) > 0 {
+		mappings["realmMappings"] = roles
+	}
+	clientMappings := object{}
+	for _, client := range r.realm.clients.items {
+		roles := r.realm.mappedRoles(id, str(client, "id"))
+		if len(roles) == 0 {
+			continue
+		}
+		clientMappings[str(client, "clientId")] = object{
+			"id":       str(client, "id"),
+			"client":   str(client, "clientId"),
+			"mappings": roles,
+		}
+	}
+	if len(clientMappings) > 0 {
+		mappings["clientMappings"] = clientMappings
+	}
+	writeJSON(w, http.StatusOK, mappings)
+}
+
+// roleContainer returns the realm ID or the ID of the client with the path
+// variable "client" or writes a 404 error.
+func (s *Server) roleContainer(w http.ResponseWriter, r *request) (string, bool) {
+	if _, ok := r.vars["client"]; !ok {
+		return str(r.realm.rep, "id"), true
+	}
+	if _, ok := s.clientRolesOf(w, r); !ok {
+		return "", false
+	}
+	return r.vars["client"], true
+}
+
+func (s *Server) listEffectiveRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	container, ok := s.roleContainer(w, r)
+	if !ok {
+		return
+	}
+	effective := r.realm.effectiveRoles(id)
+	writeJSON(w, http.StatusOK, r.realm.rolesIn(container, func(roleID string) bool {
+		return effective[roleID]
+	}))
+}
+
+func (s *Server) listAvailableRoleMappings(w http.ResponseWriter, r *request) {
+	id, ok := s.subject(w, r)
+	if !ok {
+		return
+	}
+	container, ok := s.roleContainer(w, r)
+	if !ok {
+		return
+	}
+	effective := r.realm.effectiveRoles(id)
+	writeJSON(w, http.StatusOK, r.realm.rolesIn(container, func(roleID string) bool {
+		return !effective[roleID]
+	}))
+}
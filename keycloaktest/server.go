@@ -0,0 +1,313 @@
+// Package keycloaktest provides an in-memory fake of the Keycloak admin API
+// for unit tests.
+//
+//	fake := keycloaktest.NewServer()
+//	defer fake.Close()
+//
+//	kc, err := keycloak.NewKeycloak(fake.Client(), fake.URL)
+//
+// The fake keeps all data in memory, creates the same default clients, roles
+// and client scopes as Keycloak for every new realm and answers with the same
+// Location headers and 404 and 409 errors. Authentication is not checked.
+//
+// Admin events are recorded for successful changes if they are enabled for
+// the realm. Since the fake has no login, user events are only recorded for
+// impersonations or added with AddEvent.
+package keycloaktest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// object is the JSON representation of a Keycloak entity.
+type object = map[string]interface{}
+
+// Server is a fake Keycloak server. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server with a trailing slash, e.g.
+	// "http://127.0.0.1:51234/".
+	URL string
+
+	server *httptest.Server
+	routes []route
+
+	mu     sync.Mutex
+	realms *collection
+	state  map[string]*realm
+}
+
+// NewServer starts and returns a new fake Keycloak server with a "master"
+// realm. The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		realms: newCollection("id"),
+		state:  map[string]*realm{},
+	}
+	s.registerRealms()
+	s.registerUsers()
+	s.registerGroups()
+	s.registerRoles()
+	s.registerClients()
+	s.registerClientScopes()
+	s.registerAuthz()
+	s.registerProtocolMappers()
+	s.registerPartialImport()
+	s.registerAttackDetection()
+	s.registerIdentityProviders()
+	s.registerEvents()
+
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL + "/"
+
+	s.createRealm(object{"id": "master", "realm": "master", "enabled": true})
+	return s
+}
+
+// Client returns an HTTP client configured for making requests to the
+// server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Close shuts down the server and blocks until all outstanding requests
+// have completed.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// request is an incoming request together with its path variables.
+type request struct {
+	*http.Request
+	vars  map[string]string
+	realm *realm
+}
+
+// handler handles a request. The server lock is held while it runs.
+type handler func(w http.ResponseWriter, r *request)
+
+// route maps a method and a path pattern like
+// "admin/realms/{realm}/users/{id}" to a handler.
+type route struct {
+	method   string
+	segments []string
+	handler  handler
+	// unlocked handlers run without the server lock and without the realm
+	// of the request. They take the lock themselves.
+	unlocked bool
+}
+
+func (s *Server) handle(method, pattern string, h handler) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(pattern, "/"),
+		handler:  h,
+	})
+}
+
+// handleUnlocked is like handle but for handlers which wait for other
+// servers and must not block the fake meanwhile.
+func (s *Server) handleUnlocked(method, pattern string, h handler) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(pattern, "/"),
+		handler:  h,
+		unlocked: true,
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+
+	pathFound := false
+	for _, rt := range s.routes {
+		vars, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		pathFound = true
+		if rt.method != r.Method {
+			continue
+		}
+
+		if rt.unlocked {
+			rt.handler(w, &request{Request: r, vars: vars})
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		req := &request{Request: r, vars: vars}
+		if name, ok := vars["realm"]; ok {
+			req.realm = s.state[name]
+			if req.realm == nil {
+				writeError(w, http.StatusNotFound, "Realm not found.")
+				return
+			}
+		}
+		if req.realm != nil && r.Method != http.MethodGet && strings.HasPrefix(r.URL.Path, "/admin/") {
+			s.serveAdminChange(w, req, rt.handler)
+			return
+		}
+		rt.handler(w, req)
+		return
+	}
+
+	if pathFound {
+		writeError(w, http.StatusMethodNotAllowed, "HTTP 405 Method Not Allowed")
+		return
+	}
+	writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	vars := map[string]string{}
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") {
+			v, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			vars[strings.Trim(seg, "{}")] = v
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return vars, true
+}
+
+// location returns the absolute URL of path for the Location header.
+func (s *Server) location(path ...string) string {
+	escaped := make([]string, len(path))
+	for i, p := range path {
+		escaped[i] = url.PathEscape(p)
+	}
+	return s.URL + strings.Join(escaped, "/")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format Keycloak uses for 404 and most
+// other errors.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, object{"error": message})
+}
+
+// writeConflict writes an error in the format Keycloak uses for 409.
+func writeConflict(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusConflict, object{"errorMessage": message})
+}
+
+func created(w http.ResponseWriter, location string) {
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func noContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body into v and writes a 400 error if
+// that fails.
+func decode(w http.ResponseWriter, r *request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "unknown_error")
+		return false
+	}
+	return true
+}
+
+// paginate applies the "first" and "max" query parameters.
+func paginate(items []object, r *request) []object {
+	q := r.URL.Query()
+	if first, err := strconv.Atoi(q.Get("first")); err == nil && first > 0 {
+		if first > len(items) {
+			first = len(items)
+		}
+		items = items[first:]
+	}
+	if max, err := strconv.Atoi(q.Get("max")); err == nil && max >= 0 && max < len(items) {
+		items = items[:max]
+	}
+	if items == nil {
+		items = []object{}
+	}
+	return items
+}
+
+// newID returns a random UUID.
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func now() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// str returns the string value of key or "".
+func str(o object, key string) string {
+	v, _ := o[key].(string)
+	return v
+}
+
+// boolean returns the bool value of key or false.
+func boolean(o object, key string) bool {
+	v, _ := o[key].(bool)
+	return v
+}
+
+// copyObject returns a shallow copy of o so handlers never hand out the
+// stored object.
+func copyObject(o object) object {
+	c := make(object, len(o))
+	for k, v := range o {
+		c[k] = v
+	}
+	return c
+}
+
+// merge copies all fields of update into o except the ID.
+func merge(o, update object, idKey string) {
+	for k, v := range update {
+		if k == idKey {
+			continue
+		}
+		o[k] = v
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
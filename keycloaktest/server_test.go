@@ -0,0 +1,1232 @@
+package keycloaktest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zemirco/keycloak/v2"
+	"github.com/zemirco/keycloak/v2/keycloaktest"
+)
+
+// setup starts a fake server with the realm "test".
+func setup(t *testing.T) *keycloak.Keycloak {
+	t.Helper()
+
+	fake := keycloaktest.NewServer()
+	t.Cleanup(fake.Close)
+
+	k, err := keycloak.NewKeycloak(fake.Client(), fake.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	realm := &keycloak.Realm{
+		Enabled: keycloak.Bool(true),
+		Realm:   keycloak.String("test"),
+	}
+	if _, err := k.Realms.Create(context.Background(), realm); err != nil {
+		t.Fatalf("Realms.Create returned error: %v", err)
+	}
+	return k
+}
+
+func TestServer_realms(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	realms, _, err := k.Realms.List(ctx)
+	if err != nil {
+		t.Errorf("Realms.List returned error: %v", err)
+	}
+	if len(realms) != 2 {
+		t.Errorf("got: %d, want: %d", len(realms), 2)
+	}
+
+	_, err = k.Realms.Create(ctx, &keycloak.Realm{Realm: keycloak.String("test")})
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	_, _, err = k.Realms.Get(ctx, "unknown")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+
+	roles, _, err := k.RealmRoles.List(ctx, "test", nil)
+	if err != nil {
+		t.Errorf("RealmRoles.List returned error: %v", err)
+	}
+	if len(roles) != 3 {
+		t.Errorf("got: %d, want: %d", len(roles), 3)
+	}
+
+	if _, err := k.Realms.Delete(ctx, "test"); err != nil {
+		t.Errorf("Realms.Delete returned error: %v", err)
+	}
+	_, _, err = k.Realms.Get(ctx, "test")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_users(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	user, res, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{
+		Username: keycloak.String("John"),
+		Email:    keycloak.String("john@wayne.com"),
+		Enabled:  keycloak.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+	if *user.Username != "john" {
+		t.Errorf("got: %s, want: %s", *user.Username, "john")
+	}
+
+	_, err = k.Users.Create(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	users, _, err := k.Users.GetByUsername(ctx, "test", "john")
+	if err != nil {
+		t.Errorf("Users.GetByUsername returned error: %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("got: %d, want: %d", len(users), 1)
+	}
+
+	if _, err := k.Users.Delete(ctx, "test", *user.ID); err != nil {
+		t.Errorf("Users.Delete returned error: %v", err)
+	}
+	_, _, err = k.Users.GetByID(ctx, "test", *user.ID)
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_groups(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	group, _, err := k.Groups.CreateAndGet(ctx, "test", &keycloak.Group{Name: keycloak.String("admins")})
+	if err != nil {
+		t.Fatalf("Groups.CreateAndGet returned error: %v", err)
+	}
+	if *group.Path != "/admins" {
+		t.Errorf("got: %s, want: %s", *group.Path, "/admins")
+	}
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+	if _, err := k.Users.JoinGroup(ctx, "test", *user.ID, *group.ID); err != nil {
+		t.Errorf("Users.JoinGroup returned error: %v", err)
+	}
+
+	role, _, err := k.RealmRoles.CreateAndGet(ctx, "test", &keycloak.Role{Name: keycloak.String("admin")})
+	if err != nil {
+		t.Fatalf("RealmRoles.CreateAndGet returned error: %v", err)
+	}
+	if _, err := k.Groups.AddRealmRoles(ctx, "test", *group.ID, []*keycloak.Role{role}); err != nil {
+		t.Errorf("Groups.AddRealmRoles returned error: %v", err)
+	}
+
+	groups, _, err := k.Groups.List(ctx, "test", nil)
+	if err != nil {
+		t.Errorf("Groups.List returned error: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Errorf("got: %d, want: %d", len(groups), 1)
+	}
+
+	if _, err := k.Groups.Delete(ctx, "test", *group.ID); err != nil {
+		t.Errorf("Groups.Delete returned error: %v", err)
+	}
+}
+
+func TestServer_clients(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	clients, _, err := k.Clients.List(ctx, "test", nil)
+	if err != nil {
+		t.Errorf("Clients.List returned error: %v", err)
+	}
+	if len(clients) != 6 {
+		t.Errorf("got: %d, want: %d", len(clients), 6)
+	}
+
+	client, _, err := k.Clients.CreateAndGet(ctx, "test", &keycloak.Client{
+		ClientID:                     keycloak.String("myclient"),
+		AuthorizationServicesEnabled: keycloak.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("Clients.CreateAndGet returned error: %v", err)
+	}
+	if !*client.ServiceAccountsEnabled {
+		t.Error("got: false, want: true")
+	}
+
+	secret, _, err := k.Clients.GetSecret(ctx, "test", *client.ID)
+	if err != nil {
+		t.Errorf("Clients.GetSecret returned error: %v", err)
+	}
+	if secret.Value == nil || *secret.Value == "" {
+		t.Error("got: empty secret, want: secret")
+	}
+
+	role := &keycloak.Role{Name: keycloak.String("reader")}
+	if _, err := k.ClientRoles.Create(ctx, "test", *client.ID, role); err != nil {
+		t.Errorf("ClientRoles.Create returned error: %v", err)
+	}
+	_, err = k.ClientRoles.Create(ctx, "test", *client.ID, role)
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	roles, _, err := k.ClientRoles.List(ctx, "test", *client.ID, nil)
+	if err != nil {
+		t.Errorf("ClientRoles.List returned error: %v", err)
+	}
+	// uma_protection is created together with the authorization settings
+	if len(roles) != 2 {
+		t.Errorf("got: %d, want: %d", len(roles), 2)
+	}
+
+	id, _, err := k.Clients.GetByClientID(ctx, "test", "myclient")
+	if err != nil {
+		t.Errorf("Clients.GetByClientID returned error: %v", err)
+	}
+	if id != *client.ID {
+		t.Errorf("got: %s, want: %s", id, *client.ID)
+	}
+
+	if _, err := k.Clients.Delete(ctx, "test", id); err != nil {
+		t.Errorf("Clients.Delete returned error: %v", err)
+	}
+	_, _, err = k.Clients.GetByClientID(ctx, "test", "myclient")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_clientScopes(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	scope, _, err := k.ClientScopes.CreateAndGet(ctx, "test", &keycloak.ClientScope{
+		Name:     keycloak.String("custom"),
+		Protocol: keycloak.String("openid-connect"),
+	})
+	if err != nil {
+		t.Fatalf("ClientScopes.CreateAndGet returned error: %v", err)
+	}
+
+	scopes, _, err := k.ClientScopes.List(ctx, "test")
+	if err != nil {
+		t.Errorf("ClientScopes.List returned error: %v", err)
+	}
+	if len(scopes) != 11 {
+		t.Errorf("got: %d, want: %d", len(scopes), 11)
+	}
+
+	if _, err := k.ClientScopes.Delete(ctx, "test", *scope.ID); err != nil {
+		t.Errorf("ClientScopes.Delete returned error: %v", err)
+	}
+}
+
+func TestServer_authz(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	client, _, err := k.Clients.CreateAndGet(ctx, "test", &keycloak.Client{
+		ClientID:                     keycloak.String("myclient"),
+		AuthorizationServicesEnabled: keycloak.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("Clients.CreateAndGet returned error: %v", err)
+	}
+
+	resource, res, err := k.Resources.Create(ctx, "test", *client.ID, &keycloak.Resource{
+		Name: keycloak.String("resource"),
+	})
+	if err != nil {
+		t.Fatalf("Resources.Create returned error: %v", err)
+	}
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusCreated)
+	}
+
+	_, _, err = k.Resources.Create(ctx, "test", *client.ID, &keycloak.Resource{
+		Name: keycloak.String("resource"),
+	})
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	resources, _, err := k.Resources.List(ctx, "test", *client.ID, nil)
+	if err != nil {
+		t.Errorf("Resources.List returned error: %v", err)
+	}
+	if len(resources) != 2 {
+		t.Errorf("got: %d, want: %d", len(resources), 2)
+	}
+
+	if _, err := k.Resources.Delete(ctx, "test", *client.ID, *resource.ID); err != nil {
+		t.Errorf("Resources.Delete returned error: %v", err)
+	}
+
+	policies, _, err := k.Policies.List(ctx, "test", *client.ID, nil)
+	if err != nil {
+		t.Errorf("Policies.List returned error: %v", err)
+	}
+	if len(policies) != 1 {
+		t.Errorf("got: %d, want: %d", len(policies), 1)
+	}
+
+	permissions, _, err := k.Permissions.List(ctx, "test", *client.ID, nil)
+	if err != nil {
+		t.Errorf("Permissions.List returned error: %v", err)
+	}
+	if len(permissions) != 1 {
+		t.Errorf("got: %d, want: %d", len(permissions), 1)
+	}
+}
+
+func TestServer_serviceAccount(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	client, _, err := k.Clients.CreateAndGet(ctx, "test", &keycloak.Client{
+		ClientID:               keycloak.String("myclient"),
+		ServiceAccountsEnabled: keycloak.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("Clients.CreateAndGet returned error: %v", err)
+	}
+
+	user, _, err := k.Clients.GetServiceAccountUser(ctx, "test", *client.ID)
+	if err != nil {
+		t.Fatalf("Clients.GetServiceAccountUser returned error: %v", err)
+	}
+	if *user.Username != "service-account-myclient" {
+		t.Errorf("got: %s, want: %s", *user.Username, "service-account-myclient")
+	}
+
+	if _, err := k.Clients.AddServiceAccountRealmRoles(ctx, "test", *client.ID, "offline_access"); err != nil {
+		t.Errorf("Clients.AddServiceAccountRealmRoles returned error: %v", err)
+	}
+	roles, _, err := k.Users.ListRealmRoles(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListRealmRoles returned error: %v", err)
+	}
+	// default-roles-test and offline_access
+	if len(roles) != 2 {
+		t.Errorf("got: %d, want: %d", len(roles), 2)
+	}
+
+	if _, err := k.Clients.AddServiceAccountRealmManagementRoles(ctx, "test", *client.ID, "view-users"); err != nil {
+		t.Errorf("Clients.AddServiceAccountRealmManagementRoles returned error: %v", err)
+	}
+	_, err = k.Clients.AddServiceAccountRealmManagementRoles(ctx, "test", *client.ID, "unknown")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_protocolMappers(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	id, _, err := k.Clients.GetByClientID(ctx, "test", "account")
+	if err != nil {
+		t.Fatalf("Clients.GetByClientID returned error: %v", err)
+	}
+
+	res, err := k.Clients.CreateProtocolMapper(ctx, "test", id, keycloak.NewAudienceMapper("audience", "account"))
+	if err != nil {
+		t.Fatalf("Clients.CreateProtocolMapper returned error: %v", err)
+	}
+	mapperID, err := keycloak.IDFromLocation(res)
+	if err != nil {
+		t.Fatalf("IDFromLocation returned error: %v", err)
+	}
+
+	_, err = k.Clients.CreateProtocolMapper(ctx, "test", id, keycloak.NewAudienceMapper("audience", "account"))
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	mapper, _, err := k.Clients.GetProtocolMapper(ctx, "test", id, mapperID)
+	if err != nil {
+		t.Errorf("Clients.GetProtocolMapper returned error: %v", err)
+	}
+	if *mapper.ProtocolMapper != "oidc-audience-mapper" {
+		t.Errorf("got: %s, want: %s", *mapper.ProtocolMapper, "oidc-audience-mapper")
+	}
+
+	if _, err := k.Clients.DeleteProtocolMapper(ctx, "test", id, mapperID); err != nil {
+		t.Errorf("Clients.DeleteProtocolMapper returned error: %v", err)
+	}
+
+	scope, _, err := k.ClientScopes.CreateAndGet(ctx, "test", &keycloak.ClientScope{Name: keycloak.String("custom")})
+	if err != nil {
+		t.Fatalf("ClientScopes.CreateAndGet returned error: %v", err)
+	}
+
+	mappers := []*keycloak.ProtocolMapper{
+		keycloak.NewHardcodedClaimMapper("tenant", "tenant", "acme"),
+		keycloak.NewUserAttributeMapper("department", "department", "department"),
+	}
+	if _, err := k.ClientScopes.AddProtocolMappers(ctx, "test", *scope.ID, mappers); err != nil {
+		t.Errorf("ClientScopes.AddProtocolMappers returned error: %v", err)
+	}
+
+	list, _, err := k.ClientScopes.ListProtocolMappers(ctx, "test", *scope.ID)
+	if err != nil {
+		t.Errorf("ClientScopes.ListProtocolMappers returned error: %v", err)
+	}
+	if len(list) != 2 {
+		t.Errorf("got: %d, want: %d", len(list), 2)
+	}
+}
+
+func TestServer_clientScopeAssignments(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	scope, _, err := k.ClientScopes.CreateAndGet(ctx, "test", &keycloak.ClientScope{
+		Name:     keycloak.String("tenant"),
+		Protocol: keycloak.String("openid-connect"),
+	})
+	if err != nil {
+		t.Fatalf("ClientScopes.CreateAndGet returned error: %v", err)
+	}
+
+	if _, err := k.Realms.AddDefaultDefaultClientScope(ctx, "test", *scope.ID); err != nil {
+		t.Errorf("Realms.AddDefaultDefaultClientScope returned error: %v", err)
+	}
+
+	client, _, err := k.Clients.CreateAndGet(ctx, "test", &keycloak.Client{ClientID: keycloak.String("myclient")})
+	if err != nil {
+		t.Fatalf("Clients.CreateAndGet returned error: %v", err)
+	}
+
+	scopes, _, err := k.Clients.ListDefaultClientScopes(ctx, "test", *client.ID)
+	if err != nil {
+		t.Errorf("Clients.ListDefaultClientScopes returned error: %v", err)
+	}
+	// acr, email, profile, roles, web-origins and tenant
+	if len(scopes) != 6 {
+		t.Errorf("got: %d, want: %d", len(scopes), 6)
+	}
+
+	if _, err := k.Clients.RemoveDefaultClientScope(ctx, "test", *client.ID, *scope.ID); err != nil {
+		t.Errorf("Clients.RemoveDefaultClientScope returned error: %v", err)
+	}
+	if _, err := k.Clients.AddOptionalClientScope(ctx, "test", *client.ID, *scope.ID); err != nil {
+		t.Errorf("Clients.AddOptionalClientScope returned error: %v", err)
+	}
+
+	scopes, _, err = k.Clients.ListOptionalClientScopes(ctx, "test", *client.ID)
+	if err != nil {
+		t.Errorf("Clients.ListOptionalClientScopes returned error: %v", err)
+	}
+	// address, microprofile-jwt, offline_access, phone and tenant
+	if len(scopes) != 5 {
+		t.Errorf("got: %d, want: %d", len(scopes), 5)
+	}
+
+	_, err = k.Clients.AddOptionalClientScope(ctx, "test", *client.ID, "unknown")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+
+	found, _, err := k.ClientScopes.GetByName(ctx, "test", "tenant")
+	if err != nil {
+		t.Errorf("ClientScopes.GetByName returned error: %v", err)
+	}
+	if *found.ID != *scope.ID {
+		t.Errorf("got: %s, want: %s", *found.ID, *scope.ID)
+	}
+}
+
+func TestServer_userSearch(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	for _, user := range []*keycloak.User{
+		{Username: keycloak.String("john"), Enabled: keycloak.Bool(true), Attributes: &map[string][]string{"tenant": {"acme"}}},
+		{Username: keycloak.String("johnny"), Enabled: keycloak.Bool(false)},
+		{Username: keycloak.String("a+b&c"), Enabled: keycloak.Bool(true)},
+	} {
+		if _, err := k.Users.Create(ctx, "test", user); err != nil {
+			t.Fatalf("Users.Create returned error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		opts  *keycloak.UsersListOptions
+		count int
+	}{
+		{nil, 3},
+		{&keycloak.UsersListOptions{Username: "john"}, 2},
+		{&keycloak.UsersListOptions{Username: "john", Exact: keycloak.Bool(true)}, 1},
+		{&keycloak.UsersListOptions{Enabled: keycloak.Bool(false)}, 1},
+		{&keycloak.UsersListOptions{Q: "tenant:acme"}, 1},
+		{&keycloak.UsersListOptions{Username: "a+b&c"}, 1},
+	}
+
+	for _, tt := range tests {
+		users, _, err := k.Users.List(ctx, "test", tt.opts)
+		if err != nil {
+			t.Errorf("Users.List returned error: %v", err)
+		}
+		if len(users) != tt.count {
+			t.Errorf("got: %d, want: %d", len(users), tt.count)
+		}
+
+		count, _, err := k.Users.Count(ctx, "test", tt.opts)
+		if err != nil {
+			t.Errorf("Users.Count returned error: %v", err)
+		}
+		if count != tt.count {
+			t.Errorf("got: %d, want: %d", count, tt.count)
+		}
+	}
+}
+
+func TestServer_sessions(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+
+	sessions, _, err := k.Users.ListSessions(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListSessions returned error: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("got: %d, want: %d", len(sessions), 0)
+	}
+
+	consents, _, err := k.Users.ListConsents(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListConsents returned error: %v", err)
+	}
+	if len(consents) != 0 {
+		t.Errorf("got: %d, want: %d", len(consents), 0)
+	}
+
+	_, err = k.Users.RevokeConsent(ctx, "test", *user.ID, "account")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+
+	if _, err := k.Users.Logout(ctx, "test", *user.ID); err != nil {
+		t.Errorf("Users.Logout returned error: %v", err)
+	}
+
+	_, err = k.Realms.DeleteSession(ctx, "test", "unknown")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+
+	if _, _, err := k.Realms.LogoutAll(ctx, "test"); err != nil {
+		t.Errorf("Realms.LogoutAll returned error: %v", err)
+	}
+}
+
+func TestServer_credentials(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+
+	credential := &keycloak.Credential{
+		Type:  keycloak.String("password"),
+		Value: keycloak.String("secret"),
+	}
+	if _, err := k.Users.ResetPassword(ctx, "test", *user.ID, credential); err != nil {
+		t.Errorf("Users.ResetPassword returned error: %v", err)
+	}
+
+	credentials, _, err := k.Users.ListCredentials(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListCredentials returned error: %v", err)
+	}
+	if len(credentials) != 1 {
+		t.Fatalf("got: %d, want: %d", len(credentials), 1)
+	}
+	if credentials[0].Value != nil {
+		t.Errorf("got: %s, want: nil", *credentials[0].Value)
+	}
+
+	if _, err := k.Users.UpdateCredentialLabel(ctx, "test", *user.ID, *credentials[0].ID, "my password"); err != nil {
+		t.Errorf("Users.UpdateCredentialLabel returned error: %v", err)
+	}
+	if _, err := k.Users.MoveCredentialFirst(ctx, "test", *user.ID, *credentials[0].ID); err != nil {
+		t.Errorf("Users.MoveCredentialFirst returned error: %v", err)
+	}
+
+	credentials, _, err = k.Users.ListCredentials(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListCredentials returned error: %v", err)
+	}
+	if *credentials[0].UserLabel != "my password" {
+		t.Errorf("got: %s, want: %s", *credentials[0].UserLabel, "my password")
+	}
+
+	if _, err := k.Users.DisableCredentialTypes(ctx, "test", *user.ID, []string{"otp"}); err != nil {
+		t.Errorf("Users.DisableCredentialTypes returned error: %v", err)
+	}
+
+	if _, err := k.Users.DeleteCredential(ctx, "test", *user.ID, *credentials[0].ID); err != nil {
+		t.Errorf("Users.DeleteCredential returned error: %v", err)
+	}
+	_, err = k.Users.DeleteCredential(ctx, "test", *user.ID, *credentials[0].ID)
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_federatedIdentities(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+
+	link := &keycloak.FederatedIdentity{
+		UserID:   keycloak.String("12345"),
+		UserName: keycloak.String("john@github"),
+	}
+	_, err = k.Users.AddFederatedIdentity(ctx, "test", *user.ID, "github", link)
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+
+	idp := &keycloak.IdentityProvider{
+		Alias:      keycloak.String("github"),
+		ProviderID: keycloak.String("github"),
+	}
+	if _, err := k.IdentityProviders.Create(ctx, "test", idp); err != nil {
+		t.Fatalf("IdentityProviders.Create returned error: %v", err)
+	}
+	if _, err := k.Users.AddFederatedIdentity(ctx, "test", *user.ID, "github", link); err != nil {
+		t.Errorf("Users.AddFederatedIdentity returned error: %v", err)
+	}
+	_, err = k.Users.AddFederatedIdentity(ctx, "test", *user.ID, "github", link)
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	identities, _, err := k.Users.ListFederatedIdentities(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListFederatedIdentities returned error: %v", err)
+	}
+	if len(identities) != 1 {
+		t.Fatalf("got: %d, want: %d", len(identities), 1)
+	}
+	if *identities[0].IdentityProvider != "github" {
+		t.Errorf("got: %s, want: %s", *identities[0].IdentityProvider, "github")
+	}
+
+	if _, err := k.Users.RemoveFederatedIdentity(ctx, "test", *user.ID, "github"); err != nil {
+		t.Errorf("Users.RemoveFederatedIdentity returned error: %v", err)
+	}
+	_, err = k.Users.RemoveFederatedIdentity(ctx, "test", *user.ID, "github")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_roleMappings(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	role, _, err := k.RealmRoles.CreateAndGet(ctx, "test", &keycloak.Role{Name: keycloak.String("role")})
+	if err != nil {
+		t.Fatalf("RealmRoles.CreateAndGet returned error: %v", err)
+	}
+	group, _, err := k.Groups.CreateAndGet(ctx, "test", &keycloak.Group{Name: keycloak.String("group")})
+	if err != nil {
+		t.Fatalf("Groups.CreateAndGet returned error: %v", err)
+	}
+	if _, err := k.Groups.AddRealmRoles(ctx, "test", *group.ID, []*keycloak.Role{role}); err != nil {
+		t.Errorf("Groups.AddRealmRoles returned error: %v", err)
+	}
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+	if _, err := k.Users.JoinGroup(ctx, "test", *user.ID, *group.ID); err != nil {
+		t.Errorf("Users.JoinGroup returned error: %v", err)
+	}
+
+	mappings, _, err := k.Users.GetRoleMappings(ctx, "test", *user.ID)
+	if err != nil {
+		t.Fatalf("Users.GetRoleMappings returned error: %v", err)
+	}
+	// default-roles-test
+	if len(mappings.RealmMappings) != 1 {
+		t.Errorf("got: %d, want: %d", len(mappings.RealmMappings), 1)
+	}
+
+	// the role of the group and the default roles with their composites
+	roles, _, err := k.Users.ListCompositeRealmRoles(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListCompositeRealmRoles returned error: %v", err)
+	}
+	if len(roles) != 4 {
+		t.Errorf("got: %d, want: %d", len(roles), 4)
+	}
+
+	roles, _, err = k.Users.ListAvailableRealmRoles(ctx, "test", *user.ID)
+	if err != nil {
+		t.Errorf("Users.ListAvailableRealmRoles returned error: %v", err)
+	}
+	if len(roles) != 0 {
+		t.Errorf("got: %d, want: %d", len(roles), 0)
+	}
+
+	roles, _, err = k.Groups.ListAvailableRealmRoles(ctx, "test", *group.ID)
+	if err != nil {
+		t.Errorf("Groups.ListAvailableRealmRoles returned error: %v", err)
+	}
+	// default-roles-test, offline_access and uma_authorization
+	if len(roles) != 3 {
+		t.Errorf("got: %d, want: %d", len(roles), 3)
+	}
+
+	client, _, err := k.Clients.CreateAndGet(ctx, "test", &keycloak.Client{ClientID: keycloak.String("myclient")})
+	if err != nil {
+		t.Fatalf("Clients.CreateAndGet returned error: %v", err)
+	}
+	if _, err := k.ClientRoles.Create(ctx, "test", *client.ID, &keycloak.Role{Name: keycloak.String("client-role")}); err != nil {
+		t.Errorf("ClientRoles.Create returned error: %v", err)
+	}
+	clientRole, _, err := k.ClientRoles.Get(ctx, "test", *client.ID, "client-role")
+	if err != nil {
+		t.Fatalf("ClientRoles.Get returned error: %v", err)
+	}
+	if _, err := k.Groups.AddClientRoles(ctx, "test", *group.ID, *client.ID, []*keycloak.Role{clientRole}); err != nil {
+		t.Errorf("Groups.AddClientRoles returned error: %v", err)
+	}
+
+	mappings, _, err = k.Groups.GetRoleMappings(ctx, "test", *group.ID)
+	if err != nil {
+		t.Fatalf("Groups.GetRoleMappings returned error: %v", err)
+	}
+	if mappings.ClientMappings["myclient"] == nil || len(mappings.ClientMappings["myclient"].Mappings) != 1 {
+		t.Errorf("got: %v, want: client-role of myclient", mappings.ClientMappings)
+	}
+
+	roles, _, err = k.Users.ListCompositeClientRoles(ctx, "test", *user.ID, *client.ID)
+	if err != nil {
+		t.Errorf("Users.ListCompositeClientRoles returned error: %v", err)
+	}
+	if len(roles) != 1 {
+		t.Errorf("got: %d, want: %d", len(roles), 1)
+	}
+
+	roles, _, err = k.Users.ListClientRoles(ctx, "test", *user.ID, *client.ID)
+	if err != nil {
+		t.Errorf("Users.ListClientRoles returned error: %v", err)
+	}
+	if len(roles) != 0 {
+		t.Errorf("got: %d, want: %d", len(roles), 0)
+	}
+}
+
+func TestServer_userGroups(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+	for _, name := range []string{"admins", "developers", "designers", "others"} {
+		group, _, err := k.Groups.CreateAndGet(ctx, "test", &keycloak.Group{Name: keycloak.String(name)})
+		if err != nil {
+			t.Fatalf("Groups.CreateAndGet returned error: %v", err)
+		}
+		if name == "others" {
+			continue
+		}
+		if _, err := k.Users.JoinGroup(ctx, "test", *user.ID, *group.ID); err != nil {
+			t.Errorf("Users.JoinGroup returned error: %v", err)
+		}
+	}
+
+	groups, err := k.Users.GroupsPager("test", *user.ID, &keycloak.UserGroupsListOptions{Options: keycloak.Options{Max: 2}}).All(ctx)
+	if err != nil {
+		t.Errorf("Users.GroupsPager returned error: %v", err)
+	}
+	if len(groups) != 3 {
+		t.Errorf("got: %d, want: %d", len(groups), 3)
+	}
+
+	count, _, err := k.Users.CountGroups(ctx, "test", *user.ID, "de")
+	if err != nil {
+		t.Errorf("Users.CountGroups returned error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("got: %d, want: %d", count, 2)
+	}
+}
+
+func TestServer_impersonate(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+
+	impersonation, _, err := k.Users.Impersonate(ctx, "test", *user.ID)
+	if err != nil {
+		t.Fatalf("Users.Impersonate returned error: %v", err)
+	}
+	if *impersonation.SameRealm {
+		t.Errorf("got: %t, want: %t", *impersonation.SameRealm, false)
+	}
+	if len(impersonation.Cookies) != 2 {
+		t.Errorf("got: %d, want: %d", len(impersonation.Cookies), 2)
+	}
+}
+
+func TestServer_attackDetection(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	user, _, err := k.Users.CreateAndGet(ctx, "test", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+
+	status, _, err := k.AttackDetection.GetBruteForceStatus(ctx, "test", *user.ID)
+	if err != nil {
+		t.Fatalf("AttackDetection.GetBruteForceStatus returned error: %v", err)
+	}
+	if *status.NumFailures != 0 {
+		t.Errorf("got: %d, want: %d", *status.NumFailures, 0)
+	}
+
+	if _, err := k.AttackDetection.ClearBruteForce(ctx, "test", *user.ID); err != nil {
+		t.Errorf("AttackDetection.ClearBruteForce returned error: %v", err)
+	}
+	if _, err := k.AttackDetection.ClearAllBruteForce(ctx, "test"); err != nil {
+		t.Errorf("AttackDetection.ClearAllBruteForce returned error: %v", err)
+	}
+
+	_, _, err = k.AttackDetection.GetBruteForceStatus(ctx, "test", "unknown")
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_patchRealm(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	_, err := k.Realms.Patch(ctx, "test", func(realm *keycloak.Realm) {
+		realm.AccessTokenLifespan = keycloak.Int(600)
+	})
+	if err != nil {
+		t.Fatalf("Realms.Patch returned error: %v", err)
+	}
+
+	realm, _, err := k.Realms.Get(ctx, "test")
+	if err != nil {
+		t.Fatalf("Realms.Get returned error: %v", err)
+	}
+	if *realm.AccessTokenLifespan != 600 {
+		t.Errorf("got: %d, want: %d", *realm.AccessTokenLifespan, 600)
+	}
+	if !*realm.Enabled {
+		t.Errorf("got: %t, want: %t", *realm.Enabled, true)
+	}
+}
+
+func TestServer_partialImport(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	if _, err := k.Groups.Create(ctx, "test", &keycloak.Group{Name: keycloak.String("admins")}); err != nil {
+		t.Errorf("Groups.Create returned error: %v", err)
+	}
+
+	partialImport := &keycloak.PartialImport{
+		IfResourceExists: keycloak.String(keycloak.IfResourceExistsFail),
+		Users:            []*keycloak.User{{Username: keycloak.String("john")}},
+		Clients:          []*keycloak.Client{{ClientID: keycloak.String("myclient")}},
+		Groups:           []*keycloak.Group{{Name: keycloak.String("admins")}, {Name: keycloak.String("developers")}},
+		Roles: &keycloak.Roles{
+			Realm:  []*keycloak.Role{{Name: keycloak.String("role")}},
+			Client: map[string][]*keycloak.Role{"myclient": {{Name: keycloak.String("client-role")}}},
+		},
+	}
+	_, _, err := k.Realms.PartialImport(ctx, "test", partialImport)
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	partialImport.IfResourceExists = keycloak.String(keycloak.IfResourceExistsSkip)
+	results, _, err := k.Realms.PartialImport(ctx, "test", partialImport)
+	if err != nil {
+		t.Fatalf("Realms.PartialImport returned error: %v", err)
+	}
+	if *results.Added != 5 {
+		t.Errorf("got: %d, want: %d", *results.Added, 5)
+	}
+	if *results.Skipped != 1 {
+		t.Errorf("got: %d, want: %d", *results.Skipped, 1)
+	}
+
+	client, _, err := k.Clients.GetByClientID(ctx, "test", "myclient")
+	if err != nil {
+		t.Fatalf("Clients.GetByClientID returned error: %v", err)
+	}
+	if _, _, err := k.ClientRoles.Get(ctx, "test", client, "client-role"); err != nil {
+		t.Errorf("ClientRoles.Get returned error: %v", err)
+	}
+
+	partialImport.IfResourceExists = keycloak.String(keycloak.IfResourceExistsOverwrite)
+	results, _, err = k.Realms.PartialImport(ctx, "test", partialImport)
+	if err != nil {
+		t.Fatalf("Realms.PartialImport returned error: %v", err)
+	}
+	if *results.Overwritten != 6 {
+		t.Errorf("got: %d, want: %d", *results.Overwritten, 6)
+	}
+
+	realm, _, err := k.Realms.PartialExport(ctx, "test", true, true)
+	if err != nil {
+		t.Fatalf("Realms.PartialExport returned error: %v", err)
+	}
+	if len(realm.Groups) != 2 {
+		t.Errorf("got: %d, want: %d", len(realm.Groups), 2)
+	}
+	if len(realm.Roles.Client["myclient"]) != 1 {
+		t.Errorf("got: %d, want: %d", len(realm.Roles.Client["myclient"]), 1)
+	}
+	if len(realm.Users) != 0 {
+		t.Errorf("got: %d, want: %d", len(realm.Users), 0)
+	}
+
+	realm, _, err = k.Realms.PartialExport(ctx, "test", false, false)
+	if err != nil {
+		t.Fatalf("Realms.PartialExport returned error: %v", err)
+	}
+	if realm.Clients != nil || realm.Groups != nil {
+		t.Errorf("got: clients and groups, want: none")
+	}
+}
+
+func TestServer_identityProviders(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	idp := &keycloak.IdentityProvider{
+		Alias:      keycloak.String("oidc"),
+		ProviderID: keycloak.String("oidc"),
+		Enabled:    keycloak.Bool(true),
+	}
+	idp, _, err := k.IdentityProviders.CreateAndGet(ctx, "test", idp)
+	if err != nil {
+		t.Fatalf("IdentityProviders.CreateAndGet returned error: %v", err)
+	}
+	if idp.InternalID == nil {
+		t.Errorf("got: nil, want: internal id")
+	}
+	_, err = k.IdentityProviders.Create(ctx, "test", idp)
+	if !keycloak.IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	idp.DisplayName = keycloak.String("OpenID Connect")
+	if _, err := k.IdentityProviders.Update(ctx, "test", idp); err != nil {
+		t.Errorf("IdentityProviders.Update returned error: %v", err)
+	}
+	idps, _, err := k.IdentityProviders.List(ctx, "test", &keycloak.IdentityProvidersListOptions{Search: "oi"})
+	if err != nil {
+		t.Errorf("IdentityProviders.List returned error: %v", err)
+	}
+	if len(idps) != 1 || *idps[0].DisplayName != "OpenID Connect" {
+		t.Errorf("got: %v, want: updated identity provider", idps)
+	}
+
+	mapper := &keycloak.IdentityProviderMapper{
+		Name:                   keycloak.String("email"),
+		IdentityProviderMapper: keycloak.String("oidc-user-attribute-idp-mapper"),
+		Config: &map[string]string{
+			"claim":          "email",
+			"user.attribute": "email",
+		},
+	}
+	res, err := k.IdentityProviders.CreateMapper(ctx, "test", "oidc", mapper)
+	if err != nil {
+		t.Fatalf("IdentityProviders.CreateMapper returned error: %v", err)
+	}
+	mapperID, err := keycloak.IDFromLocation(res)
+	if err != nil {
+		t.Fatalf("IDFromLocation returned error: %v", err)
+	}
+	mapper, _, err = k.IdentityProviders.GetMapper(ctx, "test", "oidc", mapperID)
+	if err != nil {
+		t.Fatalf("IdentityProviders.GetMapper returned error: %v", err)
+	}
+	if *mapper.IdentityProviderAlias != "oidc" {
+		t.Errorf("got: %s, want: %s", *mapper.IdentityProviderAlias, "oidc")
+	}
+
+	types, _, err := k.IdentityProviders.ListMapperTypes(ctx, "test", "oidc")
+	if err != nil {
+		t.Errorf("IdentityProviders.ListMapperTypes returned error: %v", err)
+	}
+	if types["oidc-user-attribute-idp-mapper"] == nil {
+		t.Errorf("got: %v, want: oidc-user-attribute-idp-mapper", types)
+	}
+
+	permissions, _, err := k.IdentityProviders.SetManagementPermissions(ctx, "test", "oidc", true)
+	if err != nil {
+		t.Errorf("IdentityProviders.SetManagementPermissions returned error: %v", err)
+	}
+	if !*permissions.Enabled || (*permissions.ScopePermissions)["token-exchange"] == "" {
+		t.Errorf("got: %v, want: enabled permissions", permissions)
+	}
+
+	config, _, err := k.IdentityProviders.ImportConfig(ctx, "test", "oidc", k.BaseURL.String()+"realms/test/.well-known/openid-configuration")
+	if err != nil {
+		t.Errorf("IdentityProviders.ImportConfig returned error: %v", err)
+	}
+	want := k.BaseURL.String() + "realms/test/protocol/openid-connect/token"
+	if config["tokenUrl"] != want {
+		t.Errorf("got: %s, want: %s", config["tokenUrl"], want)
+	}
+
+	discovery := strings.NewReader(`{"issuer": "https://example.com", "jwks_uri": "https://example.com/jwks"}`)
+	config, _, err = k.IdentityProviders.ImportConfigFile(ctx, "test", "oidc", discovery)
+	if err != nil {
+		t.Errorf("IdentityProviders.ImportConfigFile returned error: %v", err)
+	}
+	if config["jwksUrl"] != "https://example.com/jwks" {
+		t.Errorf("got: %s, want: %s", config["jwksUrl"], "https://example.com/jwks")
+	}
+
+	if _, err := k.IdentityProviders.Delete(ctx, "test", "oidc"); err != nil {
+		t.Errorf("IdentityProviders.Delete returned error: %v", err)
+	}
+	_, _, err = k.IdentityProviders.GetMapper(ctx, "test", "oidc", mapperID)
+	if !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestServer_events(t *testing.T) {
+	fake := keycloaktest.NewServer()
+	t.Cleanup(fake.Close)
+
+	k, err := keycloak.NewKeycloak(fake.Client(), fake.URL)
+	if err != nil {
+		t.Fatalf("NewKeycloak returned error: %v", err)
+	}
+	ctx := context.Background()
+
+	config := &keycloak.RealmEventsConfig{
+		EventsEnabled:             keycloak.Bool(true),
+		AdminEventsEnabled:        keycloak.Bool(true),
+		AdminEventsDetailsEnabled: keycloak.Bool(true),
+	}
+	if _, err := k.Events.UpdateConfig(ctx, "master", config); err != nil {
+		t.Fatalf("Events.UpdateConfig returned error: %v", err)
+	}
+	config, _, err = k.Events.GetConfig(ctx, "master")
+	if err != nil {
+		t.Fatalf("Events.GetConfig returned error: %v", err)
+	}
+	if !*config.AdminEventsEnabled || len(config.EventsListeners) != 1 {
+		t.Errorf("got: %v, want: admin events enabled and default listener", config)
+	}
+
+	user, _, err := k.Users.CreateAndGet(ctx, "master", &keycloak.User{Username: keycloak.String("john")})
+	if err != nil {
+		t.Fatalf("Users.CreateAndGet returned error: %v", err)
+	}
+	if _, err := k.Users.Update(ctx, "master", user); err != nil {
+		t.Fatalf("Users.Update returned error: %v", err)
+	}
+	if _, _, err := k.Users.Impersonate(ctx, "master", *user.ID); err != nil {
+		t.Fatalf("Users.Impersonate returned error: %v", err)
+	}
+	for _, e := range []map[string]interface{}{
+		{"type": "LOGIN", "userId": *user.ID, "clientId": "account", "time": 1000},
+		{"type": "LOGIN_ERROR", "userId": *user.ID, "clientId": "account", "time": 2000},
+	} {
+		if err := fake.AddEvent("master", e); err != nil {
+			t.Fatalf("AddEvent returned error: %v", err)
+		}
+	}
+
+	adminEvents, _, err := k.Events.ListAdmin(ctx, "master", &keycloak.AdminEventQuery{
+		OperationTypes: []string{"CREATE", "UPDATE"},
+		ResourcePath:   "users/*",
+	})
+	if err != nil {
+		t.Fatalf("Events.ListAdmin returned error: %v", err)
+	}
+	if len(adminEvents) != 2 {
+		t.Fatalf("got: %d, want: %d", len(adminEvents), 2)
+	}
+	if *adminEvents[1].OperationType != "CREATE" || *adminEvents[1].ResourcePath != "users/"+*user.ID {
+		t.Errorf("got: %s %s, want: CREATE users/%s", *adminEvents[1].OperationType, *adminEvents[1].ResourcePath, *user.ID)
+	}
+	if *adminEvents[1].ResourceType != "USER" || adminEvents[1].Representation == nil {
+		t.Errorf("got: %s, want: USER with representation", *adminEvents[1].ResourceType)
+	}
+
+	events, _, err := k.Events.List(ctx, "master", &keycloak.EventQuery{User: *user.ID})
+	if err != nil {
+		t.Fatalf("Events.List returned error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got: %d, want: %d", len(events), 3)
+	}
+	if *events[0].Type != "IMPERSONATE" {
+		t.Errorf("got: %s, want: %s", *events[0].Type, "IMPERSONATE")
+	}
+
+	events, _, err = k.Events.List(ctx, "master", &keycloak.EventQuery{
+		Types:    []string{"LOGIN", "LOGIN_ERROR"},
+		DateFrom: "1970-01-01",
+		DateTo:   "1970-01-01",
+		Options:  keycloak.Options{Max: 1},
+	})
+	if err != nil {
+		t.Fatalf("Events.List returned error: %v", err)
+	}
+	if len(events) != 1 || *events[0].Type != "LOGIN_ERROR" {
+		t.Errorf("got: %v, want: newest login error", events)
+	}
+
+	var all []*keycloak.Event
+	pager := k.Events.Pager("master", &keycloak.EventQuery{Options: keycloak.Options{Max: 2}})
+	for pager.Next(ctx) {
+		all = append(all, pager.Value())
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatalf("Pager returned error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("got: %d, want: %d", len(all), 3)
+	}
+
+	if _, err := k.Events.ClearEvents(ctx, "master"); err != nil {
+		t.Errorf("Events.ClearEvents returned error: %v", err)
+	}
+	if _, err := k.Events.ClearAdminEvents(ctx, "master"); err != nil {
+		t.Errorf("Events.ClearAdminEvents returned error: %v", err)
+	}
+	adminEvents, _, err = k.Events.ListAdmin(ctx, "master", nil)
+	if err != nil {
+		t.Fatalf("Events.ListAdmin returned error: %v", err)
+	}
+	if len(adminEvents) != 0 {
+		t.Errorf("got: %d, want: %d", len(adminEvents), 0)
+	}
+
+	if err := fake.AddEvent("missing", map[string]interface{}{"type": "LOGIN"}); err == nil {
+		t.Errorf("got: nil, want: error")
+	}
+}
+
+func TestServer_importConfigUnlocked(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	discovery := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"issuer": "https://example.com", "token_endpoint": "https://example.com/token"}`))
+	}))
+	t.Cleanup(discovery.Close)
+
+	done := make(chan error)
+	var config map[string]string
+	go func() {
+		var err error
+		config, _, err = k.IdentityProviders.ImportConfig(ctx, "test", "oidc", discovery.URL)
+		done <- err
+	}()
+
+	// the fake answers other requests while the discovery document is fetched
+	<-entered
+	if _, _, err := k.Realms.Get(ctx, "test"); err != nil {
+		t.Errorf("Realms.Get returned error: %v", err)
+	}
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("IdentityProviders.ImportConfig returned error: %v", err)
+	}
+	if config["tokenUrl"] != "https://example.com/token" {
+		t.Errorf("got: %s, want: %s", config["tokenUrl"], "https://example.com/token")
+	}
+}
+
+func TestServer_importConfigRealmDeleted(t *testing.T) {
+	k := setup(t)
+	ctx := context.Background()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	discovery := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"issuer": "https://example.com"}`))
+	}))
+	t.Cleanup(discovery.Close)
+
+	done := make(chan error)
+	go func() {
+		_, _, err := k.IdentityProviders.ImportConfig(ctx, "test", "oidc", discovery.URL)
+		done <- err
+	}()
+
+	// the realm is deleted while the discovery document is fetched
+	<-entered
+	if _, err := k.Realms.Delete(ctx, "test"); err != nil {
+		t.Errorf("Realms.Delete returned error: %v", err)
+	}
+	close(release)
+
+	if err := <-done; !keycloak.IsNotFound(err) {
+		t.Errorf("got: %v, want: %d", err, http.StatusNotFound)
+	}
+}
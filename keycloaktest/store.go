@@ -0,0 +1,288 @@
+package keycloaktest
+
+import (
+	"strings"
+)
+
+// collection is an ordered set of objects identified by idKey.
+type collection struct {
+	idKey string
+	items []object
+}
+
+func newCollection(idKey string) *collection {
+	return &collection{idKey: idKey}
+}
+
+// add stores o and assigns a new ID unless it already has one.
+func (c *collection) add(o object) string {
+	id := str(o, c.idKey)
+	if id == "" {
+		id = newID()
+		o[c.idKey] = id
+	}
+	c.items = append(c.items, o)
+	return id
+}
+
+func (c *collection) get(id string) object {
+	for _, o := range c.items {
+		if str(o, c.idKey) == id {
+			return o
+		}
+	}
+	return nil
+}
+
+// find returns the first object whose key equals value, ignoring case.
+func (c *collection) find(key, value string) object {
+	for _, o := range c.items {
+		if strings.EqualFold(str(o, key), value) {
+			return o
+		}
+	}
+	return nil
+}
+
+func (c *collection) remove(id string) bool {
+	for i, o := range c.items {
+		if str(o, c.idKey) == id {
+			c.items = append(c.items[:i], c.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns copies of all objects for which keep returns true.
+func (c *collection) filter(keep func(o object) bool) []object {
+	items := []object{}
+	for _, o := range c.items {
+		if keep == nil || keep(o) {
+			items = append(items, copyObject(o))
+		}
+	}
+	return items
+}
+
+// realm holds all entities of a single realm.
+type realm struct {
+	rep object
+
+	users        *collection
+	groups       *collection
+	roles        *collection
+	clients      *collection
+	clientRoles  map[string]*collection
+	clientScopes *collection
+	secrets      map[string]string
+
+	// memberships maps user IDs to the IDs of their groups.
+	memberships map[string]map[string]bool
+
+	// roleMappings maps user and group IDs to the IDs of their directly
+	// assigned realm and client roles.
+	roleMappings map[string]map[string]bool
+
+	// resourceServers maps client IDs to their authorization settings.
+	resourceServers map[string]*resourceServer
+
+	// protocolMappers maps client and client scope IDs to their protocol
+	// mappers.
+	protocolMappers map[string]*collection
+
+	// credentials maps user IDs to their credentials in the order of their
+	// priority.
+	credentials map[string]*collection
+
+	// composites maps the IDs of composite roles to the IDs of their child
+	// roles.
+	composites map[string]map[string]bool
+
+	// federatedIdentities maps user IDs to their identity provider links.
+	federatedIdentities map[string]*collection
+
+	// identityProviders holds the identity providers keyed by alias and
+	// idpMappers maps their aliases to their mappers.
+	identityProviders *collection
+	idpMappers        map[string]*collection
+
+	// idpPermissions holds the internal IDs of identity providers with fine
+	// grained admin permissions.
+	idpPermissions map[string]bool
+
+	// events and adminEvents hold the recorded events in the order they
+	// occurred.
+	events      []object
+	adminEvents []object
+
+	// scopeAssignments maps client IDs and the realm ID to the IDs of their
+	// client scopes and whether these are "default" or "optional".
+	scopeAssignments map[string]map[string]string
+}
+
+// resourceServer holds the authorization services entities of a client.
+type resourceServer struct {
+	resources *collection
+	scopes    *collection
+	policies  *collection
+}
+
+func newRealm(rep object) *realm {
+	return &realm{
+		rep:                 rep,
+		users:               newCollection("id"),
+		groups:              newCollection("id"),
+		roles:               newCollection("id"),
+		clients:             newCollection("id"),
+		clientRoles:         map[string]*collection{},
+		clientScopes:        newCollection("id"),
+		secrets:             map[string]string{},
+		memberships:         map[string]map[string]bool{},
+		roleMappings:        map[string]map[string]bool{},
+		composites:          map[string]map[string]bool{},
+		resourceServers:     map[string]*resourceServer{},
+		protocolMappers:     map[string]*collection{},
+		scopeAssignments:    map[string]map[string]string{},
+		credentials:         map[string]*collection{},
+		federatedIdentities: map[string]*collection{},
+		identityProviders:   newCollection("alias"),
+		idpMappers:          map[string]*collection{},
+		idpPermissions:      map[string]bool{},
+	}
+}
+
+func (r *realm) name() string {
+	return str(r.rep, "realm")
+}
+
+// role returns the realm or client role with the given ID.
+func (r *realm) role(id string) object {
+	if role := r.roles.get(id); role != nil {
+		return role
+	}
+	for _, roles := range r.clientRoles {
+		if role := roles.get(id); role != nil {
+			return role
+		}
+	}
+	return nil
+}
+
+// mapRole assigns the role to a user or group.
+func (r *realm) mapRole(id, roleID string) {
+	if r.roleMappings[id] == nil {
+		r.roleMappings[id] = map[string]bool{}
+	}
+	r.roleMappings[id][roleID] = true
+}
+
+// unmapRole removes the role from all users, groups and composites.
+func (r *realm) unmapRole(roleID string) {
+	for _, roles := range r.roleMappings {
+		delete(roles, roleID)
+	}
+	delete(r.composites, roleID)
+	for _, children := range r.composites {
+		delete(children, roleID)
+	}
+}
+
+// addComposite adds the role with childID to the composite role with id.
+func (r *realm) addComposite(id, childID string) {
+	if r.composites[id] == nil {
+		r.composites[id] = map[string]bool{}
+	}
+	r.composites[id][childID] = true
+}
+
+// mappedRoles returns the directly assigned roles of a user or group in
+// container, i.e. the realm name or a client ID.
+func (r *realm) mappedRoles(id, container string) []object {
+	return r.rolesIn(container, func(roleID string) bool {
+		return r.roleMappings[id][roleID]
+	})
+}
+
+// effectiveRoles returns the IDs of the roles assigned to a user or group,
+// to the groups of a user and of all their composites.
+func (r *realm) effectiveRoles(id string) map[string]bool {
+	roles := map[string]bool{}
+	var add func(roleID string)
+	add = func(roleID string) {
+		if roles[roleID] {
+			return
+		}
+		roles[roleID] = true
+		for child := range r.composites[roleID] {
+			add(child)
+		}
+	}
+
+	for roleID := range r.roleMappings[id] {
+		add(roleID)
+	}
+	for groupID := range r.memberships[id] {
+		for roleID := range r.roleMappings[groupID] {
+			add(roleID)
+		}
+	}
+	return roles
+}
+
+// rolesIn returns copies of the roles in container for which keep returns
+// true.
+func (r *realm) rolesIn(container string, keep func(roleID string) bool) []object {
+	roles := []object{}
+	for _, role := range r.allRoles() {
+		if str(role, "containerId") == container && keep(str(role, "id")) {
+			roles = append(roles, copyObject(role))
+		}
+	}
+	return roles
+}
+
+// allRoles returns the realm roles followed by the roles of all clients.
+func (r *realm) allRoles() []object {
+	roles := append([]object{}, r.roles.items...)
+	for _, client := range r.clients.items {
+		if clientRoles, ok := r.clientRoles[str(client, "id")]; ok {
+			roles = append(roles, clientRoles.items...)
+		}
+	}
+	return roles
+}
+
+// assignScope assigns the client scope with scopeID to the client or realm
+// with id as "default" or "optional" scope.
+func (r *realm) assignScope(id, scopeID, kind string) {
+	if r.scopeAssignments[id] == nil {
+		r.scopeAssignments[id] = map[string]string{}
+	}
+	r.scopeAssignments[id][scopeID] = kind
+}
+
+// assignedScopes returns the client scopes of kind assigned to the client or
+// realm with id.
+func (r *realm) assignedScopes(id, kind string) []object {
+	return r.clientScopes.filter(func(scope object) bool {
+		return r.scopeAssignments[id][str(scope, "id")] == kind
+	})
+}
+
+// credentialsOf returns the credentials of the user with id.
+func (r *realm) credentialsOf(id string) *collection {
+	if r.credentials[id] == nil {
+		r.credentials[id] = newCollection("id")
+	}
+	return r.credentials[id]
+}
+
+// federatedIdentitiesOf returns the identity provider links of the user with
+// id.
+func (r *realm) federatedIdentitiesOf(id string) *collection {
+	if r.federatedIdentities[id] == nil {
+		r.federatedIdentities[id] = newCollection("identityProvider")
+	}
+	return r.federatedIdentities[id]
+}
@@ -0,0 +1,510 @@
+package keycloaktest
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) registerUsers() {
+	base := "admin/realms/{realm}/users"
+	s.handle(http.MethodGet, base, s.listUsers)
+	s.handle(http.MethodPost, base, s.postUser)
+	s.handle(http.MethodGet, base+"/count", s.countUsers)
+	s.handle(http.MethodGet, base+"/{id}", s.getUser)
+	s.handle(http.MethodPut, base+"/{id}", s.updateUser)
+	s.handle(http.MethodDelete, base+"/{id}", s.deleteUser)
+	s.handle(http.MethodPut, base+"/{id}/reset-password", s.resetPassword)
+	s.handle(http.MethodGet, base+"/{id}/credentials", s.listCredentials)
+	s.handle(http.MethodDelete, base+"/{id}/credentials/{credential}", s.deleteCredential)
+	s.handle(http.MethodPut, base+"/{id}/credentials/{credential}/userLabel", s.updateCredentialLabel)
+	s.handle(http.MethodPost, base+"/{id}/credentials/{credential}/moveToFirst", s.moveCredential)
+	s.handle(http.MethodPost, base+"/{id}/credentials/{credential}/moveAfter/{previous}", s.moveCredential)
+	s.handle(http.MethodPut, base+"/{id}/disable-credential-types", s.disableCredentialTypes)
+	s.handle(http.MethodGet, base+"/{id}/federated-identity", s.listFederatedIdentities)
+	s.handle(http.MethodPost, base+"/{id}/federated-identity/{provider}", s.addFederatedIdentity)
+	s.handle(http.MethodDelete, base+"/{id}/federated-identity/{provider}", s.removeFederatedIdentity)
+	s.handle(http.MethodGet, base+"/{id}/consents", s.listConsents)
+	s.handle(http.MethodDelete, base+"/{id}/consents/{client}", s.revokeConsent)
+	s.handle(http.MethodPost, base+"/{id}/impersonation", s.impersonate)
+	s.handle(http.MethodGet, base+"/{id}/sessions", s.listUserSessions)
+	s.handle(http.MethodGet, base+"/{id}/offline-sessions/{client}", s.listUserSessions)
+	s.handle(http.MethodPost, base+"/{id}/logout", s.userAction)
+	s.handle(http.MethodPut, base+"/{id}/send-verify-email", s.userAction)
+	s.handle(http.MethodPut, base+"/{id}/execute-actions-email", s.userAction)
+	s.handle(http.MethodGet, base+"/{id}/groups", s.listUserGroups)
+	s.handle(http.MethodGet, base+"/{id}/groups/count", s.countUserGroups)
+	s.handle(http.MethodPut, base+"/{id}/groups/{group}", s.joinGroup)
+	s.handle(http.MethodDelete, base+"/{id}/groups/{group}", s.leaveGroup)
+	s.registerRoleMappings(base + "/{id}/role-mappings")
+}
+
+// addUser stores a new user and assigns the default roles of the realm.
+func (s *Server) addUser(r *realm, user object) string {
+	credentials, _ := user["credentials"].([]interface{})
+	delete(user, "credentials")
+
+	user["username"] = strings.ToLower(str(user, "username"))
+	user["createdTimestamp"] = now()
+	for _, key := range []string{"enabled", "emailVerified", "totp"} {
+		if _, ok := user[key]; !ok {
+			user[key] = false
+		}
+	}
+	id := r.users.add(user)
+
+	if role := r.roles.find("name", "default-roles-"+r.name()); role != nil {
+		r.mapRole(id, str(role, "id"))
+	}
+
+	// the secrets are never returned
+	for _, c := range credentials {
+		credential, _ := c.(object)
+		r.credentialsOf(id).add(object{
+			"type":           str(credential, "type"),
+			"userLabel":      str(credential, "userLabel"),
+			"credentialData": str(credential, "credentialData"),
+			"createdDate":    now(),
+		})
+	}
+	return id
+}
+
+// existingUser returns the user with the same username or email as user.
+func existingUser(r *realm, user object) object {
+	if other := r.users.find("username", str(user, "username")); other != nil {
+		return other
+	}
+	if email := str(user, "email"); email != "" {
+		return r.users.find("email", email)
+	}
+	return nil
+}
+
+// user returns the user with the path variable "id" or writes a 404 error.
+func (s *Server) user(w http.ResponseWriter, r *request) object {
+	user := r.realm.users.get(r.vars["id"])
+	if user == nil {
+		writeError(w, http.StatusNotFound, "User not found")
+	}
+	return user
+}
+
+// filterUsers returns the users matching the query parameters of the users
+// and users/count endpoints.
+func filterUsers(r *request) []object {
+	q := r.URL.Query()
+	exact := q.Get("exact") == "true"
+
+	matches := func(value, want string) bool {
+		if want == "" {
+			return true
+		}
+		if exact {
+			return strings.EqualFold(value, want)
+		}
+		return strings.Contains(strings.ToLower(value), strings.ToLower(want))
+	}
+
+	return r.realm.users.filter(func(user object) bool {
+		if search := strings.Trim(q.Get("search"), "*"); search != "" {
+			found := false
+			for _, key := range []string{"username", "email", "firstName", "lastName"} {
+				if strings.Contains(strings.ToLower(str(user, key)), strings.ToLower(search)) {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		for param, key := range map[string]string{"username": "username", "email": "email", "firstName": "firstName", "lastName": "lastName"} {
+			if !matches(str(user, key), q.Get(param)) {
+				return false
+			}
+		}
+		for _, key := range []string{"enabled", "emailVerified"} {
+			if v := q.Get(key); v != "" && strconv.FormatBool(boolean(user, key)) != v {
+				return false
+			}
+		}
+		// q is a list of attributes like "key1:value1 key2:value2"
+		for _, pair := range strings.Fields(q.Get("q")) {
+			key, value, _ := strings.Cut(pair, ":")
+			if !hasAttribute(user, key, value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// hasAttribute reports whether the user has the attribute key with value.
+func hasAttribute(user object, key, value string) bool {
+	attributes, _ := user["attributes"].(map[string]interface{})
+	values, _ := attributes[key].([]interface{})
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, paginate(filterUsers(r), r))
+}
+
+func (s *Server) countUsers(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, len(filterUsers(r)))
+}
+
+func (s *Server) postUser(w http.ResponseWriter, r *request) {
+	var user object
+	if !decode(w, r, &user) {
+		return
+	}
+	delete(user, "id")
+
+	if str(user, "username") == "" {
+		writeError(w, http.StatusBadRequest, "User name is missing")
+		return
+	}
+	if other := existingUser(r.realm, user); other != nil {
+		if strings.EqualFold(str(other, "username"), str(user, "username")) {
+			writeConflict(w, "User exists with same username")
+		} else {
+			writeConflict(w, "User exists with same email")
+		}
+		return
+	}
+
+	id := s.addUser(r.realm, user)
+	created(w, s.location("admin", "realms", r.realm.name(), "users", id))
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *request) {
+	if user := s.user(w, r); user != nil {
+		writeJSON(w, http.StatusOK, user)
+	}
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *request) {
+	user := s.user(w, r)
+	if user == nil {
+		return
+	}
+
+	var update object
+	if !decode(w, r, &update) {
+		return
+	}
+	if username := str(update, "username"); username != "" {
+		if other := r.realm.users.find("username", username); other != nil && str(other, "id") != str(user, "id") {
+			writeConflict(w, "User exists with same username")
+			return
+		}
+		update["username"] = strings.ToLower(username)
+	}
+
+	merge(user, update, "id")
+	noContent(w)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	removeUser(r.realm, r.vars["id"])
+	noContent(w)
+}
+
+// removeUser deletes the user with id and everything that belongs to it.
+func removeUser(r *realm, id string) {
+	r.users.remove(id)
+	delete(r.memberships, id)
+	delete(r.roleMappings, id)
+	delete(r.credentials, id)
+	delete(r.federatedIdentities, id)
+}
+
+func (s *Server) resetPassword(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+
+	var credential object
+	if !decode(w, r, &credential) {
+		return
+	}
+	if str(credential, "value") == "" {
+		writeError(w, http.StatusBadRequest, "Password cannot be empty")
+		return
+	}
+
+	credentials := r.realm.credentialsOf(r.vars["id"])
+	if password := credentials.find("type", "password"); password != nil {
+		credentials.remove(str(password, "id"))
+	}
+	credentials.add(object{"type": "password", "createdDate": now()})
+	noContent(w)
+}
+
+func (s *Server) listCredentials(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, r.realm.credentialsOf(r.vars["id"]).filter(nil))
+}
+
+// credential returns the credential with the path variable "credential" or
+// writes a 404 error.
+func (s *Server) credential(w http.ResponseWriter, r *request) object {
+	if s.user(w, r) == nil {
+		return nil
+	}
+	credential := r.realm.credentialsOf(r.vars["id"]).get(r.vars["credential"])
+	if credential == nil {
+		writeError(w, http.StatusNotFound, "Credential not found")
+	}
+	return credential
+}
+
+func (s *Server) deleteCredential(w http.ResponseWriter, r *request) {
+	if s.credential(w, r) == nil {
+		return
+	}
+	r.realm.credentialsOf(r.vars["id"]).remove(r.vars["credential"])
+	noContent(w)
+}
+
+func (s *Server) updateCredentialLabel(w http.ResponseWriter, r *request) {
+	credential := s.credential(w, r)
+	if credential == nil {
+		return
+	}
+	label, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unknown_error")
+		return
+	}
+	credential["userLabel"] = string(label)
+	noContent(w)
+}
+
+// moveCredential moves the credential to the first position or after the
+// credential with the path variable "previous".
+func (s *Server) moveCredential(w http.ResponseWriter, r *request) {
+	credential := s.credential(w, r)
+	if credential == nil {
+		return
+	}
+	credentials := r.realm.credentialsOf(r.vars["id"])
+
+	position := 0
+	if previous, ok := r.vars["previous"]; ok {
+		if credentials.get(previous) == nil {
+			writeError(w, http.StatusNotFound, "Credential not found")
+			return
+		}
+		credentials.remove(str(credential, "id"))
+		for i, c := range credentials.items {
+			if str(c, "id") == previous {
+				position = i + 1
+			}
+		}
+	} else {
+		credentials.remove(str(credential, "id"))
+	}
+
+	credentials.items = append(credentials.items[:position], append([]object{credential}, credentials.items[position:]...)...)
+	noContent(w)
+}
+
+func (s *Server) disableCredentialTypes(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+
+	var types []string
+	if !decode(w, r, &types) {
+		return
+	}
+	credentials := r.realm.credentialsOf(r.vars["id"])
+	for _, t := range types {
+		for credential := credentials.find("type", t); credential != nil; credential = credentials.find("type", t) {
+			credentials.remove(str(credential, "id"))
+		}
+	}
+	noContent(w)
+}
+
+func (s *Server) listFederatedIdentities(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, r.realm.federatedIdentitiesOf(r.vars["id"]).filter(nil))
+}
+
+func (s *Server) addFederatedIdentity(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+
+	if r.realm.identityProviders.get(r.vars["provider"]) == nil {
+		writeError(w, http.StatusNotFound, "Could not find identity provider")
+		return
+	}
+
+	var link object
+	if !decode(w, r, &link) {
+		return
+	}
+	identities := r.realm.federatedIdentitiesOf(r.vars["id"])
+	if identities.get(r.vars["provider"]) != nil {
+		writeConflict(w, "User is already linked with provider")
+		return
+	}
+	link["identityProvider"] = r.vars["provider"]
+	identities.add(link)
+	noContent(w)
+}
+
+func (s *Server) removeFederatedIdentity(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	if !r.realm.federatedIdentitiesOf(r.vars["id"]).remove(r.vars["provider"]) {
+		writeError(w, http.StatusNotFound, "Link not found")
+		return
+	}
+	noContent(w)
+}
+
+// impersonate sets the session cookies Keycloak sets for an impersonated
+// user. The fake admin is always in the realm "master".
+func (s *Server) impersonate(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	r.realm.recordEvent(object{
+		"type":      "IMPERSONATE",
+		"clientId":  "security-admin-console",
+		"userId":    r.vars["id"],
+		"ipAddress": ip,
+		"details":   object{"impersonator": "admin", "impersonator_realm": "master"},
+	})
+
+	name := r.realm.name()
+	path := "/realms/" + name + "/"
+	for _, cookie := range []string{"KEYCLOAK_IDENTITY", "KEYCLOAK_SESSION"} {
+		http.SetCookie(w, &http.Cookie{Name: cookie, Value: newID(), Path: path, HttpOnly: cookie == "KEYCLOAK_IDENTITY"})
+	}
+	writeJSON(w, http.StatusOK, object{
+		"sameRealm": name == "master",
+		"redirect":  s.location("realms", name, "account"),
+	})
+}
+
+// listUserSessions always returns an empty list of sessions since the fake
+// has no login.
+func (s *Server) listUserSessions(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	if _, ok := r.vars["client"]; ok && s.client(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, []object{})
+}
+
+// listConsents always returns an empty list of consents since the fake has
+// no login.
+func (s *Server) listConsents(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, []object{})
+}
+
+// revokeConsent always fails since the fake has no login and hence no
+// consents or offline tokens.
+func (s *Server) revokeConsent(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	writeError(w, http.StatusNotFound, "Consent nor offline token not found")
+}
+
+// userAction accepts requests which send emails to the user.
+func (s *Server) userAction(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	noContent(w)
+}
+
+// userGroups returns the groups of the user with the path variable "id"
+// whose name contains the "search" query parameter.
+func (s *Server) userGroups(r *request) []object {
+	id := r.vars["id"]
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	return r.realm.groups.filter(func(group object) bool {
+		return r.realm.memberships[id][str(group, "id")] &&
+			strings.Contains(strings.ToLower(str(group, "name")), search)
+	})
+}
+
+func (s *Server) listUserGroups(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	groups := s.userGroups(r)
+	if r.URL.Query().Get("briefRepresentation") != "false" {
+		for _, group := range groups {
+			delete(group, "attributes")
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(groups, r))
+}
+
+func (s *Server) countUserGroups(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, object{"count": len(s.userGroups(r))})
+}
+
+func (s *Server) joinGroup(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	if r.realm.groups.get(r.vars["group"]) == nil {
+		writeError(w, http.StatusNotFound, "Group not found")
+		return
+	}
+
+	id := r.vars["id"]
+	if r.realm.memberships[id] == nil {
+		r.realm.memberships[id] = map[string]bool{}
+	}
+	r.realm.memberships[id][r.vars["group"]] = true
+	noContent(w)
+}
+
+func (s *Server) leaveGroup(w http.ResponseWriter, r *request) {
+	if s.user(w, r) == nil {
+		return
+	}
+	if r.realm.groups.get(r.vars["group"]) == nil {
+		writeError(w, http.StatusNotFound, "Group not found")
+		return
+	}
+
+	delete(r.realm.memberships[r.vars["id"]], r.vars["group"])
+	noContent(w)
+}
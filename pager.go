@@ -0,0 +1,110 @@
+package keycloak
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultPageSize is the number of items a Pager fetches per request if no
+// page size is given. It matches the default of the Keycloak API.
+const DefaultPageSize = 100
+
+// Options specifies the optional parameters to list methods that support
+// pagination.
+type Options struct {
+	// First is the index of the first item, starting at 0.
+	First int `url:"first,omitempty"`
+
+	// Max is the maximum number of items, i.e. the page size.
+	Max int `url:"max,omitempty"`
+}
+
+// ListFunc fetches a single page of items.
+type ListFunc[T any] func(ctx context.Context, opts *Options) ([]T, *http.Response, error)
+
+// Pager walks through all pages of a list endpoint. Use Next to advance to
+// the next item and Value to get it.
+//
+//	pager := kc.Users.Pager("myrealm", nil)
+//	for pager.Next(ctx) {
+//		user := pager.Value()
+//	}
+//	if err := pager.Err(); err != nil {
+//		// handle error
+//	}
+type Pager[T any] struct {
+	list ListFunc[T]
+	opts Options
+
+	items   []T
+	current T
+	last    bool
+	err     error
+}
+
+// NewPager returns a new Pager which calls list for every page. opts.First
+// sets the index of the first item and opts.Max the page size.
+func NewPager[T any](opts *Options, list ListFunc[T]) *Pager[T] {
+	p := &Pager[T]{list: list}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.Max <= 0 {
+		p.opts.Max = DefaultPageSize
+	}
+	return p
+}
+
+// Next advances the pager to the next item and fetches the next page when
+// necessary. It returns false when there are no more items or an error
+// occurred.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.err != nil {
+		return false
+	}
+
+	if len(p.items) == 0 {
+		if p.last {
+			return false
+		}
+
+		opts := p.opts
+		items, _, err := p.list(ctx, &opts)
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		// a short page is the last one
+		p.last = len(items) < p.opts.Max
+		p.opts.First += len(items)
+		p.items = items
+
+		if len(p.items) == 0 {
+			return false
+		}
+	}
+
+	p.current = p.items[0]
+	p.items = p.items[1:]
+	return true
+}
+
+// Value returns the current item.
+func (p *Pager[T]) Value() T {
+	return p.current
+}
+
+// Err returns the first error that occurred while fetching pages.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All fetches all remaining pages and returns their items.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.Next(ctx) {
+		all = append(all, p.Value())
+	}
+	return all, p.Err()
+}
@@ -0,0 +1,110 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestPager(t *testing.T) {
+	// 7 users on the server
+	var requests []string
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+
+		first, _ := strconv.Atoi(r.URL.Query().Get("first"))
+		max, _ := strconv.Atoi(r.URL.Query().Get("max"))
+
+		users := []*User{}
+		for i := first; i < first+max && i < 7; i++ {
+			users = append(users, &User{Username: String(fmt.Sprintf("user%d", i))})
+		}
+		json.NewEncoder(w).Encode(users)
+	})
+
+	pager := k.Users.Pager("first", &UsersListOptions{Options: Options{Max: 3}})
+
+	var usernames []string
+	for pager.Next(context.Background()) {
+		usernames = append(usernames, *pager.Value().Username)
+	}
+	if err := pager.Err(); err != nil {
+		t.Errorf("Pager.Err returned error: %v", err)
+	}
+
+	if len(usernames) != 7 {
+		t.Errorf("got: %d, want: %d", len(usernames), 7)
+	}
+
+	if usernames[6] != "user6" {
+		t.Errorf("got: %s, want: %s", usernames[6], "user6")
+	}
+
+	want := []string{"max=3", "first=3&max=3", "first=6&max=3"}
+	if fmt.Sprint(requests) != fmt.Sprint(want) {
+		t.Errorf("got: %v, want: %v", requests, want)
+	}
+}
+
+func TestPager_exactPages(t *testing.T) {
+	calls := 0
+	pager := NewPager(&Options{Max: 2}, func(ctx context.Context, opts *Options) ([]int, *http.Response, error) {
+		calls++
+		if opts.First >= 4 {
+			return nil, nil, nil
+		}
+		return []int{opts.First, opts.First + 1}, nil, nil
+	})
+
+	all, err := pager.All(context.Background())
+	if err != nil {
+		t.Errorf("Pager.All returned error: %v", err)
+	}
+
+	if fmt.Sprint(all) != "[0 1 2 3]" {
+		t.Errorf("got: %v, want: %v", all, "[0 1 2 3]")
+	}
+
+	// the third call returns an empty page
+	if calls != 3 {
+		t.Errorf("got: %d, want: %d", calls, 3)
+	}
+}
+
+func TestPager_error(t *testing.T) {
+	pager := NewPager(nil, func(ctx context.Context, opts *Options) ([]int, *http.Response, error) {
+		if opts.Max != DefaultPageSize {
+			t.Errorf("got: %d, want: %d", opts.Max, DefaultPageSize)
+		}
+		return nil, nil, errors.New("boom")
+	})
+
+	if pager.Next(context.Background()) {
+		t.Errorf("got: %t, want: %t", true, false)
+	}
+
+	if pager.Err() == nil || pager.Err().Error() != "boom" {
+		t.Errorf("got: %v, want: %s", pager.Err(), "boom")
+	}
+}
+
+func TestRealmRolesService_Pager(t *testing.T) {
+	var query string
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `[]`)
+	})
+
+	pager := k.RealmRoles.Pager("first", &RolesListOptions{BriefRepresentation: true, Options: Options{First: 10, Max: 5}})
+	if pager.Next(context.Background()) {
+		t.Errorf("got: %t, want: %t", true, false)
+	}
+
+	if query != "briefRepresentation=true&first=10&max=5" {
+		t.Errorf("got: %s, want: %s", query, "briefRepresentation=true&first=10&max=5")
+	}
+}
@@ -0,0 +1,69 @@
+package keycloak
+
+// What to do if a resource of a partial import exists already.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/PartialImportRepresentation.java
+const (
+	// IfResourceExistsFail aborts the import without importing anything.
+	IfResourceExistsFail = "FAIL"
+
+	// IfResourceExistsSkip keeps the existing resource.
+	IfResourceExistsSkip = "SKIP"
+
+	// IfResourceExistsOverwrite replaces the existing resource.
+	IfResourceExistsOverwrite = "OVERWRITE"
+)
+
+// The action taken for a single resource of a partial import.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/partialimport/Action.java
+const (
+	PartialImportAdded       = "ADDED"
+	PartialImportSkipped     = "SKIPPED"
+	PartialImportOverwritten = "OVERWRITTEN"
+)
+
+// Roles representation. It holds the realm roles and the roles of clients
+// keyed by their client ID.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/RolesRepresentation.java
+type Roles struct {
+	Realm  []*Role            `json:"realm,omitempty"`
+	Client map[string][]*Role `json:"client,omitempty"`
+}
+
+// PartialImport representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/PartialImportRepresentation.java
+type PartialImport struct {
+	IfResourceExists  *string             `json:"ifResourceExists,omitempty"`
+	Users             []*User             `json:"users,omitempty"`
+	Clients           []*Client           `json:"clients,omitempty"`
+	Groups            []*Group            `json:"groups,omitempty"`
+	Roles             *Roles              `json:"roles,omitempty"`
+	IdentityProviders []*IdentityProvider `json:"identityProviders,omitempty"`
+}
+
+// PartialImportResults representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/services/src/main/java/org/keycloak/partialimport/PartialImportResults.java
+type PartialImportResults struct {
+	Added       *int                   `json:"added,omitempty"`
+	Skipped     *int                   `json:"skipped,omitempty"`
+	Overwritten *int                   `json:"overwritten,omitempty"`
+	Results     []*PartialImportResult `json:"results,omitempty"`
+}
+
+// PartialImportResult is the result for a single resource.
+//
+// https://github.com/keycloak/keycloak/blob/master/services/src/main/java/org/keycloak/partialimport/PartialImportResult.java
+type PartialImportResult struct {
+	// Action is one of PartialImportAdded, PartialImportSkipped or
+	// PartialImportOverwritten.
+	Action *string `json:"action,omitempty"`
+	// ResourceType is one of "USER", "CLIENT", "GROUP", "REALM_ROLE",
+	// "CLIENT_ROLE" or "IDP".
+	ResourceType *string `json:"resourceType,omitempty"`
+	ResourceName *string `json:"resourceName,omitempty"`
+	ID           *string `json:"id,omitempty"`
+}
@@ -42,8 +42,13 @@ type ScopePermission struct {
 }
 
 // List lists all permissions.
-func (s *PermissionsService) List(ctx context.Context, realm, clientID string) ([]*Permission, *http.Response, error) {
+func (s *PermissionsService) List(ctx context.Context, realm, clientID string, opts *Options) ([]*Permission, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/permission", realm, clientID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -58,6 +63,13 @@ func (s *PermissionsService) List(ctx context.Context, realm, clientID string) (
 	return permission, res, nil
 }
 
+// Pager returns a Pager which iterates over all permissions.
+func (s *PermissionsService) Pager(realm, clientID string, opts *Options) *Pager[*Permission] {
+	return NewPager(opts, func(ctx context.Context, opts *Options) ([]*Permission, *http.Response, error) {
+		return s.List(ctx, realm, clientID, opts)
+	})
+}
+
 // CreateResourcePermission creates a new resource based permission.
 func (s *PermissionsService) CreateResourcePermission(ctx context.Context, realm, clientID string, permission *ResourcePermission) (*ResourcePermission, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/permission/resource", realm, clientID)
@@ -209,7 +209,7 @@ func TestPermissionsService_List(t *testing.T) {
 		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusCreated)
 	}
 
-	permissions, res, err := k.Permissions.List(ctx, realm, clientID)
+	permissions, res, err := k.Permissions.List(ctx, realm, clientID, nil)
 	if err != nil {
 		t.Errorf("Permissions.List returned error: %v", err)
 	}
@@ -68,8 +68,13 @@ type RoleDefinition struct {
 }
 
 // List lists all policies.
-func (s *PoliciesService) List(ctx context.Context, realm, clientID string) ([]*Policy, *http.Response, error) {
+func (s *PoliciesService) List(ctx context.Context, realm, clientID string, opts *Options) ([]*Policy, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/policy?permission=false", realm, clientID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -84,6 +89,13 @@ func (s *PoliciesService) List(ctx context.Context, realm, clientID string) ([]*
 	return policies, res, nil
 }
 
+// Pager returns a Pager which iterates over all policies.
+func (s *PoliciesService) Pager(realm, clientID string, opts *Options) *Pager[*Policy] {
+	return NewPager(opts, func(ctx context.Context, opts *Options) ([]*Policy, *http.Response, error) {
+		return s.List(ctx, realm, clientID, opts)
+	})
+}
+
 // CreateUserPolicy creates a new user policy.
 func (s *PoliciesService) CreateUserPolicy(ctx context.Context, realm, clientID string, policy *UserPolicy) (*UserPolicy, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/policy/user", realm, clientID)
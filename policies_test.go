@@ -138,7 +138,7 @@ func TestPoliciesService_List(t *testing.T) {
 	createRealm(t, k, realm)
 	clientID := createClient(t, k, realm, "client")
 
-	roles, res, err := k.Policies.List(context.Background(), realm, clientID)
+	roles, res, err := k.Policies.List(context.Background(), realm, clientID, nil)
 	if err != nil {
 		t.Errorf("Policies.List returned error: %v", err)
 	}
@@ -0,0 +1,64 @@
+package keycloak
+
+// ProtocolMapper representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/ProtocolMapperRepresentation.java
+type ProtocolMapper struct {
+	ID              *string            `json:"id,omitempty"`
+	Name            *string            `json:"name,omitempty"`
+	Protocol        *string            `json:"protocol,omitempty"`
+	ProtocolMapper  *string            `json:"protocolMapper,omitempty"`
+	ConsentRequired *bool              `json:"consentRequired,omitempty"`
+	Config          *map[string]string `json:"config,omitempty"`
+}
+
+// NewAudienceMapper returns a mapper which adds the client audience to the
+// "aud" claim of the access token.
+func NewAudienceMapper(name, audience string) *ProtocolMapper {
+	return &ProtocolMapper{
+		Name:           String(name),
+		Protocol:       String("openid-connect"),
+		ProtocolMapper: String("oidc-audience-mapper"),
+		Config: &map[string]string{
+			"included.client.audience": audience,
+			"id.token.claim":           "false",
+			"access.token.claim":       "true",
+		},
+	}
+}
+
+// NewHardcodedClaimMapper returns a mapper which adds the claim with a fixed
+// string value to the ID token, access token and userinfo.
+func NewHardcodedClaimMapper(name, claim, value string) *ProtocolMapper {
+	return &ProtocolMapper{
+		Name:           String(name),
+		Protocol:       String("openid-connect"),
+		ProtocolMapper: String("oidc-hardcoded-claim-mapper"),
+		Config: &map[string]string{
+			"claim.name":           claim,
+			"claim.value":          value,
+			"jsonType.label":       "String",
+			"id.token.claim":       "true",
+			"access.token.claim":   "true",
+			"userinfo.token.claim": "true",
+		},
+	}
+}
+
+// NewUserAttributeMapper returns a mapper which adds the user attribute as a
+// string claim to the ID token, access token and userinfo.
+func NewUserAttributeMapper(name, attribute, claim string) *ProtocolMapper {
+	return &ProtocolMapper{
+		Name:           String(name),
+		Protocol:       String("openid-connect"),
+		ProtocolMapper: String("oidc-usermodel-attribute-mapper"),
+		Config: &map[string]string{
+			"user.attribute":       attribute,
+			"claim.name":           claim,
+			"jsonType.label":       "String",
+			"id.token.claim":       "true",
+			"access.token.claim":   "true",
+			"userinfo.token.claim": "true",
+		},
+	}
+}
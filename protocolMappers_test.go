@@ -0,0 +1,45 @@
+package keycloak
+
+import (
+	"testing"
+)
+
+func TestNewAudienceMapper(t *testing.T) {
+	mapper := NewAudienceMapper("audience", "backend")
+
+	if *mapper.ProtocolMapper != "oidc-audience-mapper" {
+		t.Errorf("got: %s, want: %s", *mapper.ProtocolMapper, "oidc-audience-mapper")
+	}
+
+	if got := (*mapper.Config)["included.client.audience"]; got != "backend" {
+		t.Errorf("got: %s, want: %s", got, "backend")
+	}
+}
+
+func TestNewHardcodedClaimMapper(t *testing.T) {
+	mapper := NewHardcodedClaimMapper("tenant", "tenant", "acme")
+
+	if *mapper.ProtocolMapper != "oidc-hardcoded-claim-mapper" {
+		t.Errorf("got: %s, want: %s", *mapper.ProtocolMapper, "oidc-hardcoded-claim-mapper")
+	}
+
+	if got := (*mapper.Config)["claim.value"]; got != "acme" {
+		t.Errorf("got: %s, want: %s", got, "acme")
+	}
+}
+
+func TestNewUserAttributeMapper(t *testing.T) {
+	mapper := NewUserAttributeMapper("department", "dept", "department")
+
+	if *mapper.ProtocolMapper != "oidc-usermodel-attribute-mapper" {
+		t.Errorf("got: %s, want: %s", *mapper.ProtocolMapper, "oidc-usermodel-attribute-mapper")
+	}
+
+	if got := (*mapper.Config)["user.attribute"]; got != "dept" {
+		t.Errorf("got: %s, want: %s", got, "dept")
+	}
+
+	if got := (*mapper.Config)["claim.name"]; got != "department" {
+		t.Errorf("got: %s, want: %s", got, "department")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // Role representation
@@ -31,6 +32,22 @@ func (s *RealmRolesService) Create(ctx context.Context, realm string, role *Role
 	return s.keycloak.Do(ctx, req, nil)
 }
 
+// CreateAndGet creates a new role and returns the created role with all its properties.
+// Keycloak identifies the new role by its name in the "Location" header.
+func (s *RealmRolesService) CreateAndGet(ctx context.Context, realm string, role *Role) (*Role, *http.Response, error) {
+	res, err := s.Create(ctx, realm, role)
+	if err != nil {
+		return nil, res, err
+	}
+
+	name, err := IDFromLocation(res)
+	if err != nil {
+		return nil, res, err
+	}
+
+	return s.GetByName(ctx, realm, name)
+}
+
 // RolesListOptions ...
 type RolesListOptions struct {
 	BriefRepresentation bool `url:"briefRepresentation,omitempty"`
@@ -60,9 +77,23 @@ func (s *RealmRolesService) List(ctx context.Context, realm string, opts *RolesL
 	return roles, res, nil
 }
 
+// Pager returns a Pager which iterates over all roles.
+func (s *RealmRolesService) Pager(realm string, opts *RolesListOptions) *Pager[*Role] {
+	var filter RolesListOptions
+	if opts != nil {
+		filter = *opts
+	}
+	return NewPager(&filter.Options, func(ctx context.Context, page *Options) ([]*Role, *http.Response, error) {
+		o := filter
+		o.Options = *page
+		return s.List(ctx, realm, &o)
+	})
+}
+
 // GetByName gets role by name.
 func (s *RealmRolesService) GetByName(ctx context.Context, realm, name string) (*Role, *http.Response, error) {
-	u := fmt.Sprintf("admin/realms/%s/roles/%s", realm, name)
+	// role names may contain characters like "/" or "?"
+	u := fmt.Sprintf("admin/realms/%s/roles/%s", realm, url.PathEscape(name))
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
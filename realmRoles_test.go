@@ -43,6 +43,31 @@ func TestRealmRolesService_Create(t *testing.T) {
 	}
 }
 
+func TestRealmRolesService_CreateAndGet(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	role := &Role{
+		Name:        String("my name"),
+		Description: String("my description"),
+	}
+
+	role, res, err := k.RealmRoles.CreateAndGet(context.Background(), realm, role)
+	if err != nil {
+		t.Errorf("RealmRoles.CreateAndGet returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *role.Name != "my name" {
+		t.Errorf("got: %s, want: %s", *role.Name, "my name")
+	}
+}
+
 func TestRealmRolesService_List(t *testing.T) {
 	k := client(t)
 
@@ -117,3 +142,17 @@ func TestRealmRolesService_GetByID(t *testing.T) {
 		t.Errorf("got: %s, want: %s", *role.Name, "first")
 	}
 }
+
+func TestRealmRolesService_CreateAndGet_escapedName(t *testing.T) {
+	k := fake(t)
+
+	name := "a/b?c#d%e"
+	role, _, err := k.RealmRoles.CreateAndGet(context.Background(), "master", &Role{Name: String(name)})
+	if err != nil {
+		t.Fatalf("RealmRoles.CreateAndGet returned error: %v", err)
+	}
+
+	if *role.Name != name {
+		t.Errorf("got: %s, want: %s", *role.Name, name)
+	}
+}
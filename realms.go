@@ -1,23 +1,13 @@
 package keycloak
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
-// IdentityProvider representation.
-//
-// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/IdentityProviderRepresentation.java
-type IdentityProvider struct {
-}
-
-// IdentityProviderMapper representation.
-//
-// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/IdentityProviderMapperRepresentation.java
-type IdentityProviderMapper struct {
-}
-
 // Realm representation.
 //
 // https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/RealmRepresentation.java
@@ -101,6 +91,10 @@ type Realm struct {
 	EnabledEventTypes                                         []string                  `json:"enabledEventTypes,omitempty"`
 	AdminEventsEnabled                                        *bool                     `json:"adminEventsEnabled,omitempty"`
 	AdminEventsDetailsEnabled                                 *bool                     `json:"adminEventsDetailsEnabled,omitempty"`
+	Users                                                     []*User                   `json:"users,omitempty"`
+	Clients                                                   []*Client                 `json:"clients,omitempty"`
+	Groups                                                    []*Group                  `json:"groups,omitempty"`
+	Roles                                                     *Roles                    `json:"roles,omitempty"`
 	IdentityProviders                                         []*IdentityProvider       `json:"identityProviders,omitempty"`
 	IdentityProviderMappers                                   []*IdentityProviderMapper `json:"identityProviderMappers,omitempty"`
 	InternationalizationEnabled                               *bool                     `json:"internationalizationEnabled,omitempty"`
@@ -135,6 +129,15 @@ type Configuration struct {
 	PolicyEndpoint                             *string  `json:"policy_endpoint,omitempty"`
 }
 
+// GlobalRequestResult is the result of a request which is sent to all
+// clients, e.g. a logout of all users.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/adapters/action/GlobalRequestResult.java
+type GlobalRequestResult struct {
+	SuccessRequests []string `json:"successRequests,omitempty"`
+	FailedRequests  []string `json:"failedRequests,omitempty"`
+}
+
 // RealmsService ...
 type RealmsService service
 
@@ -181,6 +184,89 @@ func (s *RealmsService) Get(ctx context.Context, name string) (*Realm, *http.Res
 	return &realm, res, nil
 }
 
+// Update realm. Keycloak only changes the fields which are set in realm. The
+// realm is identified by name, so realm may rename it.
+func (s *RealmsService) Update(ctx context.Context, name string, realm *Realm) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s", name)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, realm)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// Patch fetches the realm, lets mutate change it and writes it back.
+//
+// Only the top-level fields changed by mutate are sent as modeled by Realm.
+// All other fields are sent back as they were fetched, so fields which Realm
+// does not model or only models partially are never overwritten. Fields set
+// to nil by mutate are left unchanged by Keycloak. Patch does not protect
+// against concurrent updates of the realm.
+func (s *RealmsService) Patch(ctx context.Context, name string, mutate func(realm *Realm)) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s", name)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if _, err := s.keycloak.Do(ctx, req, &raw); err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	var realm Realm
+	if err := json.Unmarshal(raw, &realm); err != nil {
+		return nil, err
+	}
+
+	before, err := jsonFields(&realm)
+	if err != nil {
+		return nil, err
+	}
+	mutate(&realm)
+	after, err := jsonFields(&realm)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range after {
+		if !bytes.Equal(before[key], value) {
+			fields[key] = value
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			delete(fields, key)
+		}
+	}
+
+	req, err = s.keycloak.NewRequest(http.MethodPut, u, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// jsonFields returns the top-level fields of the JSON encoding of v.
+func jsonFields(v interface{}) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
 // Delete realm.
 func (s *RealmsService) Delete(ctx context.Context, name string) (*http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s", name)
@@ -208,3 +294,159 @@ func (s *RealmsService) GetConfig(ctx context.Context, name string) (*Configurat
 
 	return &config, res, nil
 }
+
+// ListDefaultDefaultClientScopes lists the realm-wide default client scopes. They are
+// assigned to new clients automatically.
+func (s *RealmsService) ListDefaultDefaultClientScopes(ctx context.Context, name string) ([]*ClientScope, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/default-default-client-scopes", name)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var clientScopes []*ClientScope
+	res, err := s.keycloak.Do(ctx, req, &clientScopes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return clientScopes, res, nil
+}
+
+// AddDefaultDefaultClientScope adds the client scope to the realm-wide default client scopes.
+func (s *RealmsService) AddDefaultDefaultClientScope(ctx context.Context, name, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/default-default-client-scopes/%s", name, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// RemoveDefaultDefaultClientScope removes the client scope from the realm-wide default client scopes.
+func (s *RealmsService) RemoveDefaultDefaultClientScope(ctx context.Context, name, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/default-default-client-scopes/%s", name, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListDefaultOptionalClientScopes lists the realm-wide optional client scopes. They are
+// assigned to new clients automatically.
+func (s *RealmsService) ListDefaultOptionalClientScopes(ctx context.Context, name string) ([]*ClientScope, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/default-optional-client-scopes", name)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var clientScopes []*ClientScope
+	res, err := s.keycloak.Do(ctx, req, &clientScopes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return clientScopes, res, nil
+}
+
+// AddDefaultOptionalClientScope adds the client scope to the realm-wide optional client scopes.
+func (s *RealmsService) AddDefaultOptionalClientScope(ctx context.Context, name, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/default-optional-client-scopes/%s", name, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// RemoveDefaultOptionalClientScope removes the client scope from the realm-wide optional client scopes.
+func (s *RealmsService) RemoveDefaultOptionalClientScope(ctx context.Context, name, clientScopeID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/default-optional-client-scopes/%s", name, clientScopeID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// PartialExport exports the realm. Clients and groups and roles are only
+// included if requested. Keycloak masks secrets like client secrets in the
+// export.
+func (s *RealmsService) PartialExport(ctx context.Context, name string, exportClients, exportGroupsAndRoles bool) (*Realm, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/partial-export", name)
+	u, err := addOptions(u, &struct {
+		ExportClients        bool `url:"exportClients"`
+		ExportGroupsAndRoles bool `url:"exportGroupsAndRoles"`
+	}{exportClients, exportGroupsAndRoles})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var realm Realm
+	res, err := s.keycloak.Do(ctx, req, &realm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &realm, res, nil
+}
+
+// PartialImport imports users, clients, groups, roles and identity providers
+// into the realm. With IfResourceExistsFail nothing is imported if a single
+// resource exists already.
+func (s *RealmsService) PartialImport(ctx context.Context, name string, partialImport *PartialImport) (*PartialImportResults, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/partialImport", name)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, partialImport)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results PartialImportResults
+	res, err := s.keycloak.Do(ctx, req, &results)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &results, res, nil
+}
+
+// DeleteSession removes the session with the given ID, e.g. one returned by
+// UsersService.ListSessions.
+func (s *RealmsService) DeleteSession(ctx context.Context, name, sessionID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/sessions/%s", name, sessionID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// LogoutAll removes all user sessions of the realm. Clients with an admin URL
+// are notified and the result lists which of them could be reached.
+func (s *RealmsService) LogoutAll(ctx context.Context, name string) (*GlobalRequestResult, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/logout-all", name)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result GlobalRequestResult
+	res, err := s.keycloak.Do(ctx, req, &result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &result, res, nil
+}
@@ -2,6 +2,8 @@ package keycloak
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"testing"
 )
@@ -106,6 +108,187 @@ func TestRealmsService_Get(t *testing.T) {
 	}
 }
 
+func TestRealmsService_Update(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	ctx := context.Background()
+
+	res, err := k.Realms.Update(ctx, realm, &Realm{AccessTokenLifespan: Int(600)})
+	if err != nil {
+		t.Errorf("Realms.Update returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	updated, _, err := k.Realms.Get(ctx, realm)
+	if err != nil {
+		t.Fatalf("Realms.Get returned error: %v", err)
+	}
+
+	if *updated.AccessTokenLifespan != 600 {
+		t.Errorf("got: %d, want: %d", *updated.AccessTokenLifespan, 600)
+	}
+
+	// other fields are not changed
+	if (*updated.SMTPServer)["host"] != "mailhog" {
+		t.Errorf("got: %s, want: %s", (*updated.SMTPServer)["host"], "mailhog")
+	}
+}
+
+func TestRealmsService_Patch(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	ctx := context.Background()
+
+	res, err := k.Realms.Patch(ctx, realm, func(realm *Realm) {
+		realm.BruteForceProtected = Bool(true)
+	})
+	if err != nil {
+		t.Errorf("Realms.Patch returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	updated, _, err := k.Realms.Get(ctx, realm)
+	if err != nil {
+		t.Fatalf("Realms.Get returned error: %v", err)
+	}
+
+	if !*updated.BruteForceProtected {
+		t.Errorf("got: %t, want: %t", *updated.BruteForceProtected, true)
+	}
+}
+
+func TestRealmsService_Patch_unmodeledFields(t *testing.T) {
+	var body map[string]json.RawMessage
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"realm":"first","accessTokenLifespan":300,"unmodeled":{"a":1},"identityProviders":[{"alias":"github"}]}`)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := k.Realms.Patch(context.Background(), "first", func(realm *Realm) {
+		realm.AccessTokenLifespan = Int(600)
+	})
+	if err != nil {
+		t.Fatalf("Realms.Patch returned error: %v", err)
+	}
+
+	tests := map[string]string{
+		"realm":               `"first"`,
+		"accessTokenLifespan": `600`,
+		"unmodeled":           `{"a":1}`,
+		"identityProviders":   `[{"alias":"github"}]`,
+	}
+	for key, want := range tests {
+		if got := string(body[key]); got != want {
+			t.Errorf("%s got: %s, want: %s", key, got, want)
+		}
+	}
+}
+
+func TestRealmsService_PartialExport(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createClient(t, k, realm, "client")
+	createGroup(t, k, realm, "group")
+
+	exported, res, err := k.Realms.PartialExport(context.Background(), realm, true, true)
+	if err != nil {
+		t.Fatalf("Realms.PartialExport returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	found := false
+	for _, client := range exported.Clients {
+		if *client.ClientID == "client" {
+			found = true
+			// secrets are masked
+			if *client.Secret != "**********" {
+				t.Errorf("got: %s, want: %s", *client.Secret, "**********")
+			}
+		}
+	}
+	if !found {
+		t.Errorf("got: %d clients, want: client", len(exported.Clients))
+	}
+
+	if len(exported.Groups) != 1 {
+		t.Errorf("got: %d, want: %d", len(exported.Groups), 1)
+	}
+
+	if !hasRole(exported.Roles.Realm, "offline_access") {
+		t.Errorf("got: %v, want: offline_access", exported.Roles.Realm)
+	}
+}
+
+func TestRealmsService_PartialImport(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createGroup(t, k, realm, "group")
+
+	ctx := context.Background()
+
+	partialImport := &PartialImport{
+		IfResourceExists: String(IfResourceExistsFail),
+		Users:            []*User{{Username: String("user"), Enabled: Bool(true)}},
+		Groups:           []*Group{{Name: String("group")}},
+		Roles:            &Roles{Realm: []*Role{{Name: String("role")}}},
+	}
+
+	_, _, err := k.Realms.PartialImport(ctx, realm, partialImport)
+	if !IsConflict(err) {
+		t.Errorf("got: %v, want: conflict", err)
+	}
+
+	partialImport.IfResourceExists = String(IfResourceExistsSkip)
+	results, res, err := k.Realms.PartialImport(ctx, realm, partialImport)
+	if err != nil {
+		t.Fatalf("Realms.PartialImport returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *results.Added != 2 {
+		t.Errorf("got: %d, want: %d", *results.Added, 2)
+	}
+
+	if *results.Skipped != 1 {
+		t.Errorf("got: %d, want: %d", *results.Skipped, 1)
+	}
+
+	for _, result := range results.Results {
+		if *result.ResourceType == "GROUP" && *result.Action != PartialImportSkipped {
+			t.Errorf("got: %s, want: %s", *result.Action, PartialImportSkipped)
+		}
+	}
+}
+
 func TestRealmsService_Delete(t *testing.T) {
 	k := client(t)
 
@@ -139,3 +322,100 @@ func TestRealmsService_GetConfig(t *testing.T) {
 		t.Errorf("got: %s, want: %s", *config.Issuer, "http://localhost:8080/realms/first")
 	}
 }
+
+func TestRealmsService_AddDefaultDefaultClientScope(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientScopeID := createClientScope(t, k, realm, "scope")
+
+	res, err := k.Realms.AddDefaultDefaultClientScope(context.Background(), realm, clientScopeID)
+	if err != nil {
+		t.Errorf("Realms.AddDefaultDefaultClientScope returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	clientScopes, _, err := k.Realms.ListDefaultDefaultClientScopes(context.Background(), realm)
+	if err != nil {
+		t.Errorf("Realms.ListDefaultDefaultClientScopes returned error: %v", err)
+	}
+
+	found := false
+	for _, clientScope := range clientScopes {
+		if *clientScope.ID == clientScopeID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got: %v, want: %s", clientScopes, clientScopeID)
+	}
+
+	if _, err := k.Realms.RemoveDefaultDefaultClientScope(context.Background(), realm, clientScopeID); err != nil {
+		t.Errorf("Realms.RemoveDefaultDefaultClientScope returned error: %v", err)
+	}
+}
+
+func TestRealmsService_AddDefaultOptionalClientScope(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	clientScopeID := createClientScope(t, k, realm, "scope")
+
+	if _, err := k.Realms.AddDefaultOptionalClientScope(context.Background(), realm, clientScopeID); err != nil {
+		t.Errorf("Realms.AddDefaultOptionalClientScope returned error: %v", err)
+	}
+
+	clientScopes, _, err := k.Realms.ListDefaultOptionalClientScopes(context.Background(), realm)
+	if err != nil {
+		t.Errorf("Realms.ListDefaultOptionalClientScopes returned error: %v", err)
+	}
+
+	found := false
+	for _, clientScope := range clientScopes {
+		if *clientScope.ID == clientScopeID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got: %v, want: %s", clientScopes, clientScopeID)
+	}
+
+	if _, err := k.Realms.RemoveDefaultOptionalClientScope(context.Background(), realm, clientScopeID); err != nil {
+		t.Errorf("Realms.RemoveDefaultOptionalClientScope returned error: %v", err)
+	}
+}
+
+func TestRealmsService_LogoutAll(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+	login(t, k, realm, userID, "user")
+
+	_, res, err := k.Realms.LogoutAll(context.Background(), realm)
+	if err != nil {
+		t.Errorf("Realms.LogoutAll returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	sessions, _, err := k.Users.ListSessions(context.Background(), realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListSessions returned error: %v", err)
+	}
+
+	if len(sessions) != 0 {
+		t.Errorf("got: %d, want: %d", len(sessions), 0)
+	}
+}
@@ -32,8 +32,13 @@ type Resource struct {
 }
 
 // List lists all resources.
-func (s *ResourcesService) List(ctx context.Context, realm, clientID string) ([]*Resource, *http.Response, error) {
+func (s *ResourcesService) List(ctx context.Context, realm, clientID string, opts *Options) ([]*Resource, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/resource", realm, clientID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -48,6 +53,13 @@ func (s *ResourcesService) List(ctx context.Context, realm, clientID string) ([]
 	return resources, res, nil
 }
 
+// Pager returns a Pager which iterates over all resources.
+func (s *ResourcesService) Pager(realm, clientID string, opts *Options) *Pager[*Resource] {
+	return NewPager(opts, func(ctx context.Context, opts *Options) ([]*Resource, *http.Response, error) {
+		return s.List(ctx, realm, clientID, opts)
+	})
+}
+
 // Create creates a new resource.
 func (s *ResourcesService) Create(ctx context.Context, realm, clientID string, resource *Resource) (*Resource, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/resource", realm, clientID)
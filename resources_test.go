@@ -60,7 +60,7 @@ func TestResourcesService_List(t *testing.T) {
 
 	clientID := createClient(t, k, realm, "client")
 
-	resources, res, err := k.Resources.List(context.Background(), realm, clientID)
+	resources, res, err := k.Resources.List(context.Background(), realm, clientID, nil)
 	if err != nil {
 		t.Errorf("Clients.ListResources returned error: %v", err)
 	}
@@ -83,7 +83,7 @@ func TestResourcesService_Get(t *testing.T) {
 	clientID := createClient(t, k, realm, "client")
 
 	// list all resources first
-	resources, _, err := k.Resources.List(context.Background(), realm, clientID)
+	resources, _, err := k.Resources.List(context.Background(), realm, clientID, nil)
 	if err != nil {
 		t.Errorf("Clients.ListResources returned error: %v", err)
 	}
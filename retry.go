@@ -0,0 +1,209 @@
+package keycloak
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how Keycloak.Do retries failed requests.
+//
+// Keycloak answers with 503 while it is restarting and sometimes with 409 or
+// 500 under cluster cache contention. A retry policy lets those requests pass
+// after a short wait instead of failing immediately. 409 is not retried by
+// default since Keycloak uses it for real conflicts as well, like a taken
+// username, see RetryConflicts.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	MaxAttempts int
+
+	// MinBackoff is the wait time before the first retry. It doubles with every
+	// further attempt.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the wait time between two attempts, also if the server
+	// asks for a longer wait with a "Retry-After" header.
+	MaxBackoff time.Duration
+
+	// Methods are the HTTP methods which are retried. Defaults to GET, HEAD and
+	// OPTIONS. Add POST, PUT or DELETE only if you know the requests are safe
+	// to repeat.
+	Methods []string
+
+	// StatusCodes are the HTTP status codes which are retried. Defaults to 429,
+	// 500, 502, 503 and 504.
+	StatusCodes []int
+
+	// RetryConflicts also retries 409 answers to requests with an idempotent
+	// method, i.e. GET, HEAD, OPTIONS, PUT and DELETE, regardless of Methods
+	// and StatusCodes. A 409 to a POST is never retried this way, because it
+	// usually means the resource exists already.
+	RetryConflicts bool
+
+	// Retryable, if set, replaces Methods, StatusCodes and RetryConflicts and
+	// reports whether a request should be retried. Either res or err is nil.
+	Retryable func(req *http.Request, res *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns a policy with four attempts and an exponential
+// backoff between 100 milliseconds and five seconds.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  100 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	}
+}
+
+var (
+	defaultRetryMethods     = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+	idempotentMethods       = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete}
+	defaultRetryStatusCodes = []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+)
+
+// retryable reports whether the request should be sent again.
+func (p *RetryPolicy) retryable(req *http.Request, res *http.Response, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(req, res, err)
+	}
+
+	if p.RetryConflicts && err == nil && res.StatusCode == http.StatusConflict && hasMethod(idempotentMethods, req.Method) {
+		return true
+	}
+
+	methods := p.Methods
+	if methods == nil {
+		methods = defaultRetryMethods
+	}
+	if !hasMethod(methods, req.Method) {
+		return false
+	}
+
+	// network errors
+	if err != nil {
+		return true
+	}
+
+	codes := p.StatusCodes
+	if codes == nil {
+		codes = defaultRetryStatusCodes
+	}
+	for _, c := range codes {
+		if c == res.StatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait time before the next attempt. The "Retry-After"
+// header takes precedence over the exponential backoff but is capped by
+// MaxBackoff as well.
+func (p *RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if d, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+			return d
+		}
+	}
+
+	d := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// add jitter so concurrent clients do not retry in lockstep
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryAfter parses the value of a "Retry-After" header. It is either a number
+// of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// do sends the request and retries it according to the retry policy.
+func (k *Keycloak) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	policy := k.RetryPolicy
+
+	for attempt := 1; ; attempt++ {
+		res, err := k.client.Do(req)
+
+		if policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return res, err
+		}
+		// the body of the request cannot be sent again
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return res, err
+		}
+		if !policy.retryable(req, res, err) {
+			return res, err
+		}
+
+		wait := policy.backoff(attempt, res)
+		if res != nil {
+			// drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package keycloak
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_retryable(t *testing.T) {
+	var attempts int32
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"first"}`))
+	})
+	k.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	req, err := k.NewRequest(http.MethodGet, "admin/realms/first", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var realm Realm
+	res, err := k.Do(context.Background(), req, &realm)
+	if err != nil {
+		t.Errorf("Do returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if attempts != 3 {
+		t.Errorf("got: %d, want: %d", attempts, 3)
+	}
+}
+
+func TestRetryPolicy_maxAttempts(t *testing.T) {
+	var attempts int32
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	k.RetryPolicy = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+
+	req, err := k.NewRequest(http.MethodGet, "admin/realms/first", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = k.Do(context.Background(), req, nil)
+	if !hasStatus(err, http.StatusServiceUnavailable) {
+		t.Errorf("got: %v, want: %d", err, http.StatusServiceUnavailable)
+	}
+
+	if attempts != 2 {
+		t.Errorf("got: %d, want: %d", attempts, 2)
+	}
+}
+
+func TestRetryPolicy_methods(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if atomic.AddInt32(&attempts, 1) < 2 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	// POST is not retried by default
+	k.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	req, err := k.NewRequest(http.MethodPost, "admin/realms/first/users", &User{Username: String("john")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := k.Do(context.Background(), req, nil); !IsConflict(err) {
+		t.Errorf("got: %v, want: %d", err, http.StatusConflict)
+	}
+
+	// opt in to retry POST on conflicts
+	attempts = 0
+	bodies = nil
+	k.RetryPolicy = &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		Methods:     []string{http.MethodPost},
+		StatusCodes: []int{http.StatusConflict},
+	}
+
+	req, err = k.NewRequest(http.MethodPost, "admin/realms/first/users", &User{Username: String("john")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := k.Do(context.Background(), req, nil)
+	if err != nil {
+		t.Errorf("Do returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusCreated)
+	}
+
+	// the body is sent again
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Errorf("got: %q, want: two equal bodies", bodies)
+	}
+}
+
+func TestRetryPolicy_conflicts(t *testing.T) {
+	var attempts int32
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 2 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// 409 is not retried by default
+	k.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	req, err := k.NewRequest(http.MethodPut, "admin/realms/first", &Realm{Enabled: Bool(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := k.Do(context.Background(), req, nil); !IsConflict(err) {
+		t.Errorf("got: %v, want: %d", err, http.StatusConflict)
+	}
+
+	// PUT is retried on conflicts with RetryConflicts
+	attempts = 0
+	k.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, RetryConflicts: true}
+
+	req, err = k.NewRequest(http.MethodPut, "admin/realms/first", &Realm{Enabled: Bool(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := k.Do(context.Background(), req, nil); err != nil {
+		t.Errorf("Do returned error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("got: %d, want: %d", attempts, 2)
+	}
+
+	// POST is never retried on conflicts with RetryConflicts
+	attempts = 0
+
+	req, err = k.NewRequest(http.MethodPost, "admin/realms/first/users", &User{Username: String("john")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := k.Do(context.Background(), req, nil); !IsConflict(err) {
+		t.Errorf("got: %v, want: %d", err, http.StatusConflict)
+	}
+
+	if attempts != 1 {
+		t.Errorf("got: %d, want: %d", attempts, 1)
+	}
+}
+
+func TestRetryPolicy_context(t *testing.T) {
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	k.RetryPolicy = DefaultRetryPolicy()
+
+	req, err := k.NewRequest(http.MethodGet, "admin/realms/first", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := k.Do(ctx, req, nil); err != context.DeadlineExceeded {
+		t.Errorf("got: %v, want: %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		d := p.backoff(tt.attempt, nil)
+		if d < tt.min || d > tt.max {
+			t.Errorf("attempt %d got: %s, want: between %s and %s", tt.attempt, d, tt.min, tt.max)
+		}
+	}
+
+	// Retry-After is capped by MaxBackoff
+	res := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	if d := p.backoff(1, res); d != time.Second {
+		t.Errorf("got: %s, want: %s", d, time.Second)
+	}
+
+	p.MaxBackoff = 5 * time.Second
+	if d := p.backoff(1, res); d != 3*time.Second {
+		t.Errorf("got: %s, want: %s", d, 3*time.Second)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("120"); !ok || d != 2*time.Minute {
+		t.Errorf("got: %s, want: %s", d, 2*time.Minute)
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d, ok := retryAfter(date); !ok || d < 59*time.Minute || d > time.Hour {
+		t.Errorf("got: %s, want: about %s", d, time.Hour)
+	}
+
+	if _, ok := retryAfter("soon"); ok {
+		t.Errorf("got: %t, want: %t", ok, false)
+	}
+}
@@ -0,0 +1,21 @@
+package keycloak
+
+// Mappings representation. It holds all roles assigned to a user or group.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/MappingsRepresentation.java
+type Mappings struct {
+	RealmMappings  []*Role                    `json:"realmMappings,omitempty"`
+	ClientMappings map[string]*ClientMappings `json:"clientMappings,omitempty"`
+}
+
+// ClientMappings representation. It holds the roles of a single client
+// assigned to a user or group.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/ClientMappingsRepresentation.java
+type ClientMappings struct {
+	// ID of the client.
+	ID *string `json:"id,omitempty"`
+	// Client is the client ID of the client.
+	Client   *string `json:"client,omitempty"`
+	Mappings []*Role `json:"mappings,omitempty"`
+}
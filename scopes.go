@@ -20,8 +20,13 @@ type Scope struct {
 }
 
 // List lists all resources.
-func (s *ScopesService) List(ctx context.Context, realm, clientID string) ([]*Scope, *http.Response, error) {
+func (s *ScopesService) List(ctx context.Context, realm, clientID string, opts *Options) ([]*Scope, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/scope", realm, clientID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -36,6 +41,13 @@ func (s *ScopesService) List(ctx context.Context, realm, clientID string) ([]*Sc
 	return scopes, res, nil
 }
 
+// Pager returns a Pager which iterates over all scopes.
+func (s *ScopesService) Pager(realm, clientID string, opts *Options) *Pager[*Scope] {
+	return NewPager(opts, func(ctx context.Context, opts *Options) ([]*Scope, *http.Response, error) {
+		return s.List(ctx, realm, clientID, opts)
+	})
+}
+
 // Create creates a new scope.
 func (s *ScopesService) Create(ctx context.Context, realm, clientID string, scope *Scope) (*Scope, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/clients/%s/authz/resource-server/scope", realm, clientID)
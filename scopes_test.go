@@ -54,7 +54,7 @@ func TestScopesService_List(t *testing.T) {
 	createScope(t, k, realm, clientID, "read")
 	createScope(t, k, realm, clientID, "write")
 
-	scopes, res, err := k.Scopes.List(context.Background(), realm, clientID)
+	scopes, res, err := k.Scopes.List(context.Background(), realm, clientID, nil)
 	if err != nil {
 		t.Errorf("Scopes.List returned error: %v", err)
 	}
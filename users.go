@@ -24,15 +24,71 @@ type User struct {
 	NotBefore                  *int                 `json:"notBefore,omitempty"`
 	Access                     *map[string]bool     `json:"access,omitempty"`
 	Attributes                 *map[string][]string `json:"attributes,omitempty"`
+	Credentials                []*Credential        `json:"credentials,omitempty"`
+	FederatedIdentities        []*FederatedIdentity `json:"federatedIdentities,omitempty"`
 }
 
 // Credential representation.
 //
 // https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/CredentialRepresentation.java
 type Credential struct {
-	Type      *string `json:"type,omitempty"`
-	Value     *string `json:"value,omitempty"`
-	Temporary *bool   `json:"temporary,omitempty"`
+	ID             *string `json:"id,omitempty"`
+	Type           *string `json:"type,omitempty"`
+	UserLabel      *string `json:"userLabel,omitempty"`
+	CreatedDate    *int64  `json:"createdDate,omitempty"`
+	SecretData     *string `json:"secretData,omitempty"`
+	CredentialData *string `json:"credentialData,omitempty"`
+	Priority       *int    `json:"priority,omitempty"`
+	Value          *string `json:"value,omitempty"`
+	Temporary      *bool   `json:"temporary,omitempty"`
+}
+
+// FederatedIdentity representation. It links a user to the account of an
+// identity provider.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/FederatedIdentityRepresentation.java
+type FederatedIdentity struct {
+	IdentityProvider *string `json:"identityProvider,omitempty"`
+	UserID           *string `json:"userId,omitempty"`
+	UserName         *string `json:"userName,omitempty"`
+}
+
+// UserConsent representation. It lists the client scopes the user granted to
+// a client.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/UserConsentRepresentation.java
+type UserConsent struct {
+	ClientID            *string  `json:"clientId,omitempty"`
+	GrantedClientScopes []string `json:"grantedClientScopes,omitempty"`
+	CreatedDate         *int64   `json:"createdDate,omitempty"`
+	LastUpdatedDate     *int64   `json:"lastUpdatedDate,omitempty"`
+}
+
+// Impersonation is the result of UsersService.Impersonate.
+type Impersonation struct {
+	// Redirect is the URL of the account console of the impersonated user.
+	Redirect *string `json:"redirect,omitempty"`
+	// SameRealm is true if the impersonated user is in the realm of the
+	// admin.
+	SameRealm *bool `json:"sameRealm,omitempty"`
+	// Cookies are the session cookies of the impersonated user. A browser
+	// needs them to open Redirect as the user.
+	Cookies []*http.Cookie `json:"-"`
+}
+
+// UserSession representation.
+//
+// https://github.com/keycloak/keycloak/blob/master/core/src/main/java/org/keycloak/representations/idm/UserSessionRepresentation.java
+type UserSession struct {
+	ID            *string            `json:"id,omitempty"`
+	Username      *string            `json:"username,omitempty"`
+	UserID        *string            `json:"userId,omitempty"`
+	IPAddress     *string            `json:"ipAddress,omitempty"`
+	Start         *int64             `json:"start,omitempty"`
+	LastAccess    *int64             `json:"lastAccess,omitempty"`
+	RememberMe    *bool              `json:"rememberMe,omitempty"`
+	Clients       *map[string]string `json:"clients,omitempty"`
+	TransientUser *bool              `json:"transientUser,omitempty"`
 }
 
 // UsersService ...
@@ -49,9 +105,52 @@ func (s *UsersService) Create(ctx context.Context, realm string, user *User) (*h
 	return s.keycloak.Do(ctx, req, nil)
 }
 
+// CreateAndGet creates a new user and returns the created user with all its properties.
+func (s *UsersService) CreateAndGet(ctx context.Context, realm string, user *User) (*User, *http.Response, error) {
+	res, err := s.Create(ctx, realm, user)
+	if err != nil {
+		return nil, res, err
+	}
+
+	id, err := IDFromLocation(res)
+	if err != nil {
+		return nil, res, err
+	}
+
+	return s.GetByID(ctx, realm, id)
+}
+
+// UsersListOptions ...
+type UsersListOptions struct {
+	// Search is a string contained in username, first or last name, or email.
+	// The search is prefix-based by default. Use *foo* for an infix search and
+	// quotes for an exact search.
+	Search    string `url:"search,omitempty"`
+	Username  string `url:"username,omitempty"`
+	Email     string `url:"email,omitempty"`
+	FirstName string `url:"firstName,omitempty"`
+	LastName  string `url:"lastName,omitempty"`
+	// Exact makes the username, email, firstName and lastName filters match
+	// exactly instead of as a substring.
+	Exact         *bool  `url:"exact,omitempty"`
+	Enabled       *bool  `url:"enabled,omitempty"`
+	EmailVerified *bool  `url:"emailVerified,omitempty"`
+	IdpAlias      string `url:"idpAlias,omitempty"`
+	IdpUserID     string `url:"idpUserId,omitempty"`
+	// Q is a query for custom attributes in the format "key1:value1 key2:value2".
+	Q                   string `url:"q,omitempty"`
+	BriefRepresentation *bool  `url:"briefRepresentation,omitempty"`
+	Options
+}
+
 // List users.
-func (s *UsersService) List(ctx context.Context, realm string) ([]*User, *http.Response, error) {
+func (s *UsersService) List(ctx context.Context, realm string, opts *UsersListOptions) ([]*User, *http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/users", realm)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
@@ -66,38 +165,63 @@ func (s *UsersService) List(ctx context.Context, realm string) ([]*User, *http.R
 	return users, res, nil
 }
 
-// GetByID get a single user by ID.
-func (s *UsersService) GetByID(ctx context.Context, realm, id string) (*User, *http.Response, error) {
-	u := fmt.Sprintf("admin/realms/%s/users/%s", realm, id)
+// Pager returns a Pager which iterates over all users.
+func (s *UsersService) Pager(realm string, opts *UsersListOptions) *Pager[*User] {
+	var filter UsersListOptions
+	if opts != nil {
+		filter = *opts
+	}
+	return NewPager(&filter.Options, func(ctx context.Context, page *Options) ([]*User, *http.Response, error) {
+		o := filter
+		o.Options = *page
+		return s.List(ctx, realm, &o)
+	})
+}
+
+// Count returns the number of users matching opts. The first and max options
+// are ignored.
+func (s *UsersService) Count(ctx context.Context, realm string, opts *UsersListOptions) (int, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/count", realm)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
-		return nil, nil, err
+		return 0, nil, err
 	}
 
-	var user *User
-	res, err := s.keycloak.Do(ctx, req, &user)
+	var count int
+	res, err := s.keycloak.Do(ctx, req, &count)
 	if err != nil {
-		return nil, nil, err
+		return 0, nil, err
 	}
 
-	return user, res, nil
+	return count, res, nil
 }
 
-// GetByUsername get a single user by username.
-func (s *UsersService) GetByUsername(ctx context.Context, realm, username string) ([]*User, *http.Response, error) {
-	u := fmt.Sprintf("admin/realms/%s/users?username=%s", realm, username)
+// GetByID get a single user by ID.
+func (s *UsersService) GetByID(ctx context.Context, realm, id string) (*User, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s", realm, id)
 	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var users []*User
-	res, err := s.keycloak.Do(ctx, req, &users)
+	var user *User
+	res, err := s.keycloak.Do(ctx, req, &user)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return users, res, nil
+	return user, res, nil
+}
+
+// GetByUsername get users by username. Keycloak matches the username as a
+// substring so the result may contain more than one user.
+func (s *UsersService) GetByUsername(ctx context.Context, realm, username string) ([]*User, *http.Response, error) {
+	return s.List(ctx, realm, &UsersListOptions{Username: username})
 }
 
 // Update update a single user.
@@ -157,6 +281,76 @@ func (s *UsersService) LeaveGroup(ctx context.Context, realm, userID, groupID st
 	return s.keycloak.Do(ctx, req, nil)
 }
 
+// UserGroupsListOptions ...
+type UserGroupsListOptions struct {
+	// Search is a string contained in the group name.
+	Search string `url:"search,omitempty"`
+	// BriefRepresentation omits the attributes and role mappings of the
+	// groups. Keycloak defaults to true.
+	BriefRepresentation *bool `url:"briefRepresentation,omitempty"`
+	Options
+}
+
+// ListGroups lists the groups the user is a member of.
+func (s *UsersService) ListGroups(ctx context.Context, realm, userID string, opts *UserGroupsListOptions) ([]*Group, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/groups", realm, userID)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var groups []*Group
+	res, err := s.keycloak.Do(ctx, req, &groups)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return groups, res, nil
+}
+
+// GroupsPager returns a Pager which iterates over all groups of the user.
+func (s *UsersService) GroupsPager(realm, userID string, opts *UserGroupsListOptions) *Pager[*Group] {
+	var filter UserGroupsListOptions
+	if opts != nil {
+		filter = *opts
+	}
+	return NewPager(&filter.Options, func(ctx context.Context, page *Options) ([]*Group, *http.Response, error) {
+		o := filter
+		o.Options = *page
+		return s.ListGroups(ctx, realm, userID, &o)
+	})
+}
+
+// CountGroups returns the number of groups the user is a member of whose
+// name contains search. An empty search counts all groups.
+func (s *UsersService) CountGroups(ctx context.Context, realm, userID, search string) (int, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/groups/count", realm, userID)
+	u, err := addOptions(u, &UserGroupsListOptions{Search: search})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var count struct {
+		Count int `json:"count"`
+	}
+	res, err := s.keycloak.Do(ctx, req, &count)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return count.Count, res, nil
+}
+
 // AddRealmRoles adds realm roles to user.
 func (s *UsersService) AddRealmRoles(ctx context.Context, realm, userID string, roles []*Role) (*http.Response, error) {
 	u := fmt.Sprintf("admin/realms/%s/users/%s/role-mappings/realm", realm, userID)
@@ -218,6 +412,113 @@ func (s *UsersService) RemoveClientRoles(ctx context.Context, realm, userID, cli
 	return s.keycloak.Do(ctx, req, nil)
 }
 
+// GetRoleMappings returns the realm and client roles assigned to user.
+func (s *UsersService) GetRoleMappings(ctx context.Context, realm, userID string) (*Mappings, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/role-mappings", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mappings Mappings
+	res, err := s.keycloak.Do(ctx, req, &mappings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &mappings, res, nil
+}
+
+// ListCompositeRealmRoles returns the effective realm roles of user, i.e.
+// the assigned roles and the roles of its groups including the roles of all composites.
+func (s *UsersService) ListCompositeRealmRoles(ctx context.Context, realm, userID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/role-mappings/realm/composite", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListAvailableRealmRoles returns the realm roles which can still be assigned to
+// user.
+func (s *UsersService) ListAvailableRealmRoles(ctx context.Context, realm, userID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/role-mappings/realm/available", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListClientRoles returns a list of roles of the client with clientID assigned
+// to user.
+func (s *UsersService) ListClientRoles(ctx context.Context, realm, userID, clientID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/role-mappings/clients/%s", realm, userID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListCompositeClientRoles returns the effective roles of the client with
+// clientID of user including the roles of all composites.
+func (s *UsersService) ListCompositeClientRoles(ctx context.Context, realm, userID, clientID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/role-mappings/clients/%s/composite", realm, userID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
+// ListAvailableClientRoles returns the roles of the client with clientID which
+// can still be assigned to user.
+func (s *UsersService) ListAvailableClientRoles(ctx context.Context, realm, userID, clientID string) ([]*Role, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/role-mappings/clients/%s/available", realm, userID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var roles []*Role
+	res, err := s.keycloak.Do(ctx, req, &roles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roles, res, nil
+}
+
 // VerifyEmailOptions ...
 type VerifyEmailOptions struct {
 	ClientID    string `url:"client_id,omitempty"`
@@ -264,3 +565,216 @@ func (s *UsersService) ExecuteActionsEmail(ctx context.Context, realm, userID st
 
 	return s.keycloak.Do(ctx, req, nil)
 }
+
+// Impersonate creates a session for the user as if they logged in. The
+// session is identified by the returned cookies.
+func (s *UsersService) Impersonate(ctx context.Context, realm, userID string) (*Impersonation, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/impersonation", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var impersonation Impersonation
+	res, err := s.keycloak.Do(ctx, req, &impersonation)
+	if err != nil {
+		return nil, nil, err
+	}
+	impersonation.Cookies = res.Cookies()
+
+	return &impersonation, res, nil
+}
+
+// ListSessions lists the sessions of the user.
+func (s *UsersService) ListSessions(ctx context.Context, realm, userID string) ([]*UserSession, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/sessions", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sessions []*UserSession
+	res, err := s.keycloak.Do(ctx, req, &sessions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sessions, res, nil
+}
+
+// ListOfflineSessions lists the offline sessions of the user for the client
+// with the given ID.
+func (s *UsersService) ListOfflineSessions(ctx context.Context, realm, userID, clientID string) ([]*UserSession, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/offline-sessions/%s", realm, userID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sessions []*UserSession
+	res, err := s.keycloak.Do(ctx, req, &sessions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sessions, res, nil
+}
+
+// Logout removes all sessions of the user.
+func (s *UsersService) Logout(ctx context.Context, realm, userID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/logout", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListCredentials lists the credentials of the user, e.g. the password, OTP
+// devices and WebAuthn keys. The secret data is not included.
+func (s *UsersService) ListCredentials(ctx context.Context, realm, userID string) ([]*Credential, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/credentials", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var credentials []*Credential
+	res, err := s.keycloak.Do(ctx, req, &credentials)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return credentials, res, nil
+}
+
+// DeleteCredential removes a credential of the user.
+func (s *UsersService) DeleteCredential(ctx context.Context, realm, userID, credentialID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/credentials/%s", realm, userID, credentialID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// UpdateCredentialLabel updates the label of a credential of the user.
+func (s *UsersService) UpdateCredentialLabel(ctx context.Context, realm, userID, credentialID, label string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/credentials/%s/userLabel", realm, userID, credentialID)
+	req, err := s.keycloak.NewRawRequest(http.MethodPut, u, []byte(label), "text/plain")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// MoveCredentialFirst moves a credential of the user to the first position
+// in the list of credentials, i.e. it gets the highest priority.
+func (s *UsersService) MoveCredentialFirst(ctx context.Context, realm, userID, credentialID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/credentials/%s/moveToFirst", realm, userID, credentialID)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// MoveCredentialAfter moves a credential of the user to the position right
+// after the credential with previousCredentialID.
+func (s *UsersService) MoveCredentialAfter(ctx context.Context, realm, userID, credentialID, previousCredentialID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/credentials/%s/moveAfter/%s", realm, userID, credentialID, previousCredentialID)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// DisableCredentialTypes disables all credentials of the given types, e.g.
+// "otp", of the user.
+func (s *UsersService) DisableCredentialTypes(ctx context.Context, realm, userID string, types []string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/disable-credential-types", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodPut, u, types)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListFederatedIdentities lists the identity provider links of the user.
+func (s *UsersService) ListFederatedIdentities(ctx context.Context, realm, userID string) ([]*FederatedIdentity, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/federated-identity", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var identities []*FederatedIdentity
+	res, err := s.keycloak.Do(ctx, req, &identities)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return identities, res, nil
+}
+
+// AddFederatedIdentity links the user to the account of the identity provider
+// with the given alias. The link holds the user ID and user name of the
+// account at the provider.
+func (s *UsersService) AddFederatedIdentity(ctx context.Context, realm, userID, provider string, link *FederatedIdentity) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/federated-identity/%s", realm, userID, provider)
+	req, err := s.keycloak.NewRequest(http.MethodPost, u, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// RemoveFederatedIdentity removes the link of the user to the identity
+// provider with the given alias.
+func (s *UsersService) RemoveFederatedIdentity(ctx context.Context, realm, userID, provider string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/federated-identity/%s", realm, userID, provider)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
+
+// ListConsents lists the consents the user granted to clients.
+func (s *UsersService) ListConsents(ctx context.Context, realm, userID string) ([]*UserConsent, *http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/consents", realm, userID)
+	req, err := s.keycloak.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var consents []*UserConsent
+	res, err := s.keycloak.Do(ctx, req, &consents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return consents, res, nil
+}
+
+// RevokeConsent revokes the consent of the user for the client with the given
+// client ID (not the ID of the client) and the offline tokens of the user for
+// that client.
+func (s *UsersService) RevokeConsent(ctx context.Context, realm, userID, clientID string) (*http.Response, error) {
+	u := fmt.Sprintf("admin/realms/%s/users/%s/consents/%s", realm, userID, clientID)
+	req, err := s.keycloak.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.keycloak.Do(ctx, req, nil)
+}
@@ -0,0 +1,275 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultImportConcurrency is the number of parallel requests of
+// UsersService.Import if ImportOptions.Concurrency is not set.
+const DefaultImportConcurrency = 4
+
+// NewHashedPasswordCredential returns a password credential for a password
+// which is already hashed, e.g. when users are migrated from another system.
+// Keycloak verifies the password with the hash provider for algorithm, i.e.
+// "pbkdf2-sha256" or "pbkdf2-sha512". Other algorithms like "bcrypt" need a
+// custom hash provider in Keycloak. The salt may be nil if it is part of the
+// hash.
+func NewHashedPasswordCredential(algorithm string, iterations int, hash, salt []byte) *Credential {
+	secretData, _ := json.Marshal(struct {
+		Value                string              `json:"value"`
+		Salt                 []byte              `json:"salt"`
+		AdditionalParameters map[string][]string `json:"additionalParameters"`
+	}{
+		Value:                base64.StdEncoding.EncodeToString(hash),
+		Salt:                 salt,
+		AdditionalParameters: map[string][]string{},
+	})
+
+	credentialData, _ := json.Marshal(struct {
+		HashIterations       int                 `json:"hashIterations"`
+		Algorithm            string              `json:"algorithm"`
+		AdditionalParameters map[string][]string `json:"additionalParameters"`
+	}{
+		HashIterations:       iterations,
+		Algorithm:            algorithm,
+		AdditionalParameters: map[string][]string{},
+	})
+
+	return &Credential{
+		Type:           String("password"),
+		SecretData:     String(string(secretData)),
+		CredentialData: String(string(credentialData)),
+	}
+}
+
+// ImportOptions ...
+type ImportOptions struct {
+	// Concurrency is the number of parallel requests. It defaults to
+	// DefaultImportConcurrency.
+	Concurrency int
+
+	// Start is the index of the first user to import. Use ImportReport.Next
+	// of an interrupted import to resume it together with SkipExisting.
+	// Users after Next may have been created already, because with more than
+	// one concurrent request later users finish first and requests which
+	// were in flight when the import was canceled may still have succeeded.
+	Start int
+
+	// BatchSize enables the realm partial import endpoint which creates up to
+	// BatchSize users with a single request. A failed batch fails for all of
+	// its users. By default every user is created with its own request.
+	BatchSize int
+
+	// SkipExisting skips users whose username or email already exists instead
+	// of reporting them as errors.
+	SkipExisting bool
+
+	// Progress is called after every request with the current report. It is
+	// called from the importing goroutines but never concurrently.
+	Progress func(ImportReport)
+}
+
+// ImportError is the error of a single user of an import.
+type ImportError struct {
+	// Index of the user in the imported slice.
+	Index    int
+	Username string
+	Err      error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("user %d (%s): %v", e.Index, e.Username, e.Err)
+}
+
+// Unwrap returns the underlying error, e.g. an *ErrorResponse.
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ImportReport is the result of UsersService.Import.
+type ImportReport struct {
+	// Total is the number of users passed to Import.
+	Total    int
+	Imported int
+	Skipped  int
+	// Errors are the errors of all users which could not be imported,
+	// ordered by index.
+	Errors []*ImportError
+	// Next is the index of the first user which has not been processed. All
+	// users before Next have either been imported, skipped or failed.
+	Next int
+}
+
+// importResult is the outcome for a single user.
+type importResult struct {
+	skipped bool
+	err     error
+}
+
+// importer collects the results of the importing goroutines.
+type importer struct {
+	mu       sync.Mutex
+	report   ImportReport
+	done     map[int]bool
+	progress func(ImportReport)
+}
+
+func (imp *importer) finish(ctx context.Context, start int, users []*User, results []importResult) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	for i, result := range results {
+		// users which failed because the import was canceled are not
+		// processed and will be retried when the import is resumed
+		if result.err != nil && ctx.Err() != nil {
+			continue
+		}
+
+		index := start + i
+		imp.done[index] = true
+		switch {
+		case result.err != nil:
+			username := ""
+			if users[index].Username != nil {
+				username = *users[index].Username
+			}
+			imp.report.Errors = append(imp.report.Errors, &ImportError{Index: index, Username: username, Err: result.err})
+		case result.skipped:
+			imp.report.Skipped++
+		default:
+			imp.report.Imported++
+		}
+	}
+
+	for imp.done[imp.report.Next] {
+		delete(imp.done, imp.report.Next)
+		imp.report.Next++
+	}
+
+	if imp.progress != nil {
+		report := imp.report
+		// limit the capacity so the callback cannot modify our slice
+		report.Errors = report.Errors[:len(report.Errors):len(report.Errors)]
+		imp.progress(report)
+	}
+}
+
+// Import creates many users with bounded parallelism, e.g. to migrate users
+// from another system. Errors of single users are collected in the report
+// and do not stop the import. The returned error is only set if ctx is
+// canceled, in which case the report tells where to resume.
+func (s *UsersService) Import(ctx context.Context, realm string, users []*User, opts *ImportOptions) (*ImportReport, error) {
+	var o ImportOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultImportConcurrency
+	}
+	if o.Start < 0 || o.Start > len(users) {
+		return nil, fmt.Errorf("keycloak: import start %d out of range [0, %d]", o.Start, len(users))
+	}
+	for i, user := range users {
+		if user == nil {
+			return nil, fmt.Errorf("keycloak: import user %d is nil", i)
+		}
+	}
+	size := 1
+	if o.BatchSize > 0 {
+		size = o.BatchSize
+	}
+
+	imp := &importer{
+		report:   ImportReport{Total: len(users), Next: o.Start},
+		done:     map[int]bool{},
+		progress: o.Progress,
+	}
+
+	starts := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + size
+				if end > len(users) {
+					end = len(users)
+				}
+
+				var results []importResult
+				if o.BatchSize > 0 {
+					results = s.importBatch(ctx, realm, users[start:end], o.SkipExisting)
+				} else {
+					results = []importResult{s.importUser(ctx, realm, users[start], o.SkipExisting)}
+				}
+				imp.finish(ctx, start, users, results)
+			}
+		}()
+	}
+
+	var err error
+loop:
+	for start := o.Start; start < len(users); start += size {
+		select {
+		case starts <- start:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		}
+	}
+	close(starts)
+	wg.Wait()
+
+	if err == nil && imp.report.Next < len(users) {
+		// requests of the last users failed because ctx was canceled
+		err = ctx.Err()
+	}
+
+	sort.Slice(imp.report.Errors, func(i, j int) bool {
+		return imp.report.Errors[i].Index < imp.report.Errors[j].Index
+	})
+	return &imp.report, err
+}
+
+func (s *UsersService) importUser(ctx context.Context, realm string, user *User, skipExisting bool) importResult {
+	_, err := s.Create(ctx, realm, user)
+	if err != nil && skipExisting && IsConflict(err) {
+		return importResult{skipped: true}
+	}
+	return importResult{err: err}
+}
+
+func (s *UsersService) importBatch(ctx context.Context, realm string, users []*User, skipExisting bool) []importResult {
+	results := make([]importResult, len(users))
+
+	partialImport := &PartialImport{IfResourceExists: String(IfResourceExistsFail), Users: users}
+	if skipExisting {
+		partialImport.IfResourceExists = String(IfResourceExistsSkip)
+	}
+
+	result, _, err := s.keycloak.Realms.PartialImport(ctx, realm, partialImport)
+	if err != nil {
+		for i := range results {
+			results[i].err = err
+		}
+		return results
+	}
+
+	skipped := map[string]bool{}
+	for _, r := range result.Results {
+		if r.Action != nil && *r.Action == PartialImportSkipped && r.ResourceName != nil {
+			skipped[strings.ToLower(*r.ResourceName)] = true
+		}
+	}
+	for i, user := range users {
+		results[i].skipped = user.Username != nil && skipped[strings.ToLower(*user.Username)]
+	}
+	return results
+}
@@ -0,0 +1,300 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zemirco/keycloak/v2/keycloaktest"
+)
+
+// fake returns a client for an in-memory Keycloak with the realm "master".
+func fake(t *testing.T) *Keycloak {
+	t.Helper()
+
+	server := keycloaktest.NewServer()
+	t.Cleanup(server.Close)
+
+	k, err := NewKeycloak(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// users returns n new users named user0, user1, ...
+func users(n int) []*User {
+	users := make([]*User, n)
+	for i := range users {
+		users[i] = &User{Username: String(fmt.Sprintf("user%d", i))}
+	}
+	return users
+}
+
+func TestNewHashedPasswordCredential(t *testing.T) {
+	credential := NewHashedPasswordCredential("pbkdf2-sha256", 27500, []byte("hash"), []byte("salt"))
+
+	var secretData struct {
+		Value string `json:"value"`
+		Salt  string `json:"salt"`
+	}
+	if err := json.Unmarshal([]byte(*credential.SecretData), &secretData); err != nil {
+		t.Fatal(err)
+	}
+	if secretData.Value != base64.StdEncoding.EncodeToString([]byte("hash")) {
+		t.Errorf("got: %s, want: %s", secretData.Value, base64.StdEncoding.EncodeToString([]byte("hash")))
+	}
+	if secretData.Salt != base64.StdEncoding.EncodeToString([]byte("salt")) {
+		t.Errorf("got: %s, want: %s", secretData.Salt, base64.StdEncoding.EncodeToString([]byte("salt")))
+	}
+
+	var credentialData struct {
+		HashIterations int    `json:"hashIterations"`
+		Algorithm      string `json:"algorithm"`
+	}
+	if err := json.Unmarshal([]byte(*credential.CredentialData), &credentialData); err != nil {
+		t.Fatal(err)
+	}
+	if credentialData.HashIterations != 27500 {
+		t.Errorf("got: %d, want: %d", credentialData.HashIterations, 27500)
+	}
+	if credentialData.Algorithm != "pbkdf2-sha256" {
+		t.Errorf("got: %s, want: %s", credentialData.Algorithm, "pbkdf2-sha256")
+	}
+}
+
+func TestUsersService_Import(t *testing.T) {
+	k := fake(t)
+	ctx := context.Background()
+
+	list := users(50)
+	// a duplicate of user3
+	list[10] = &User{Username: String("user3")}
+	list[20].Credentials = []*Credential{NewHashedPasswordCredential("pbkdf2-sha256", 27500, []byte("hash"), []byte("salt"))}
+
+	calls := 0
+	next := 0
+	report, err := k.Users.Import(ctx, "master", list, &ImportOptions{
+		Concurrency: 8,
+		Progress: func(report ImportReport) {
+			calls++
+			if report.Next < next {
+				t.Errorf("got: %d, want: >= %d", report.Next, next)
+			}
+			next = report.Next
+		},
+	})
+	if err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	if calls != 50 {
+		t.Errorf("got: %d, want: %d", calls, 50)
+	}
+
+	if report.Imported != 49 {
+		t.Errorf("got: %d, want: %d", report.Imported, 49)
+	}
+
+	if report.Next != 50 {
+		t.Errorf("got: %d, want: %d", report.Next, 50)
+	}
+
+	// either user3 or its duplicate fails depending on the order
+	if len(report.Errors) != 1 {
+		t.Fatalf("got: %d, want: %d", len(report.Errors), 1)
+	}
+	if !IsConflict(report.Errors[0]) {
+		t.Errorf("got: %v, want: conflict", report.Errors[0])
+	}
+
+	count, _, err := k.Users.Count(ctx, "master", nil)
+	if err != nil {
+		t.Errorf("Users.Count returned error: %v", err)
+	}
+	if count != 49 {
+		t.Errorf("got: %d, want: %d", count, 49)
+	}
+
+	imported, _, err := k.Users.GetByUsername(ctx, "master", "user20")
+	if err != nil {
+		t.Fatalf("Users.GetByUsername returned error: %v", err)
+	}
+	credentials, _, err := k.Users.ListCredentials(ctx, "master", *imported[0].ID)
+	if err != nil {
+		t.Errorf("Users.ListCredentials returned error: %v", err)
+	}
+	if len(credentials) != 1 {
+		t.Errorf("got: %d, want: %d", len(credentials), 1)
+	}
+}
+
+func TestUsersService_Import_skipExisting(t *testing.T) {
+	k := fake(t)
+	ctx := context.Background()
+
+	if _, err := k.Users.Import(ctx, "master", users(5), nil); err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	report, err := k.Users.Import(ctx, "master", users(10), &ImportOptions{SkipExisting: true})
+	if err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	if report.Imported != 5 {
+		t.Errorf("got: %d, want: %d", report.Imported, 5)
+	}
+
+	if report.Skipped != 5 {
+		t.Errorf("got: %d, want: %d", report.Skipped, 5)
+	}
+}
+
+func TestUsersService_Import_nilUser(t *testing.T) {
+	k := fake(t)
+
+	list := users(3)
+	list[1] = nil
+
+	report, err := k.Users.Import(context.Background(), "master", list, nil)
+	if err == nil || err.Error() != "keycloak: import user 1 is nil" {
+		t.Errorf("got: %v, want: keycloak: import user 1 is nil", err)
+	}
+
+	if report != nil {
+		t.Errorf("got: %v, want: nil", report)
+	}
+}
+
+func TestUsersService_Import_batch(t *testing.T) {
+	k := fake(t)
+	ctx := context.Background()
+
+	if _, err := k.Users.Import(ctx, "master", users(5), nil); err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	report, err := k.Users.Import(ctx, "master", users(25), &ImportOptions{BatchSize: 10, SkipExisting: true})
+	if err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	if report.Imported != 20 {
+		t.Errorf("got: %d, want: %d", report.Imported, 20)
+	}
+
+	if report.Skipped != 5 {
+		t.Errorf("got: %d, want: %d", report.Skipped, 5)
+	}
+
+	// without skipping every batch with an existing user fails as a whole
+	report, err = k.Users.Import(ctx, "master", users(35), &ImportOptions{BatchSize: 10})
+	if err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	if report.Imported != 5 {
+		t.Errorf("got: %d, want: %d", report.Imported, 5)
+	}
+
+	if len(report.Errors) != 30 {
+		t.Errorf("got: %d, want: %d", len(report.Errors), 30)
+	}
+}
+
+func TestUsersService_Import_resume(t *testing.T) {
+	k := fake(t)
+
+	list := users(40)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	report, err := k.Users.Import(ctx, "master", list, &ImportOptions{
+		Concurrency: 1,
+		Progress: func(report ImportReport) {
+			if report.Next == 10 {
+				cancel()
+			}
+		},
+	})
+	if err != context.Canceled {
+		t.Errorf("got: %v, want: %v", err, context.Canceled)
+	}
+
+	if report.Next < 10 || report.Next == 40 {
+		t.Fatalf("got: %d, want: between 10 and 40", report.Next)
+	}
+
+	report, err = k.Users.Import(context.Background(), "master", list, &ImportOptions{Start: report.Next})
+	if err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	if len(report.Errors) != 0 {
+		t.Errorf("got: %v, want: no errors", report.Errors)
+	}
+
+	count, _, err := k.Users.Count(context.Background(), "master", nil)
+	if err != nil {
+		t.Errorf("Users.Count returned error: %v", err)
+	}
+	if count != 40 {
+		t.Errorf("got: %d, want: %d", count, 40)
+	}
+}
+
+func TestUsersService_Import_resumeConcurrent(t *testing.T) {
+	k := fake(t)
+
+	list := users(40)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	report, err := k.Users.Import(ctx, "master", list, &ImportOptions{
+		Concurrency: 8,
+		Progress: func(report ImportReport) {
+			if report.Imported >= 10 {
+				cancel()
+			}
+		},
+	})
+	if err != context.Canceled {
+		t.Errorf("got: %v, want: %v", err, context.Canceled)
+	}
+
+	if report.Next == 40 {
+		t.Fatalf("got: %d, want: less than 40", report.Next)
+	}
+
+	// users after Next may exist already
+	next := report.Next
+	report, err = k.Users.Import(context.Background(), "master", list, &ImportOptions{
+		Concurrency:  8,
+		Start:        next,
+		SkipExisting: true,
+	})
+	if err != nil {
+		t.Fatalf("Users.Import returned error: %v", err)
+	}
+
+	if len(report.Errors) != 0 {
+		t.Errorf("got: %v, want: no errors", report.Errors)
+	}
+
+	if report.Imported+report.Skipped != 40-next {
+		t.Errorf("got: %d, want: %d", report.Imported+report.Skipped, 40-next)
+	}
+
+	count, _, err := k.Users.Count(context.Background(), "master", nil)
+	if err != nil {
+		t.Errorf("Users.Count returned error: %v", err)
+	}
+	if count != 40 {
+		t.Errorf("got: %d, want: %d", count, 40)
+	}
+}
@@ -2,6 +2,8 @@ package keycloak
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"strings"
@@ -25,8 +27,10 @@ func createUser(t *testing.T, k *Keycloak, realm string, username string) string
 		t.Errorf("Users.Create returned error: %v", err)
 	}
 
-	parts := strings.Split(res.Header.Get("Location"), "/")
-	userID := parts[len(parts)-1]
+	userID, err := IDFromLocation(res)
+	if err != nil {
+		t.Errorf("IDFromLocation returned error: %v", err)
+	}
 	return userID
 }
 
@@ -59,6 +63,35 @@ func TestUsersService_Create(t *testing.T) {
 	}
 }
 
+func TestUsersService_CreateAndGet(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	user := &User{
+		Enabled:  Bool(true),
+		Username: String("username"),
+	}
+
+	user, res, err := k.Users.CreateAndGet(context.Background(), realm, user)
+	if err != nil {
+		t.Errorf("Users.CreateAndGet returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if user.ID == nil {
+		t.Errorf("got: %v, want: user ID", user.ID)
+	}
+
+	if *user.Username != "username" {
+		t.Errorf("got: %s, want: %s", *user.Username, "username")
+	}
+}
+
 func TestUsersService_GetByID(t *testing.T) {
 	k := client(t)
 
@@ -153,7 +186,7 @@ func TestUsersService_List(t *testing.T) {
 	createUser(t, k, realm, "john")
 	createUser(t, k, realm, "mark")
 
-	users, res, err := k.Users.List(context.Background(), realm)
+	users, res, err := k.Users.List(context.Background(), realm, nil)
 	if err != nil {
 		t.Errorf("Users.List returned error: %v", err)
 	}
@@ -168,6 +201,26 @@ func TestUsersService_List(t *testing.T) {
 	}
 }
 
+func TestUsersService_Pager(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	for i := 0; i < 5; i++ {
+		createUser(t, k, realm, fmt.Sprintf("user%d", i))
+	}
+
+	users, err := k.Users.Pager(realm, &UsersListOptions{Options: Options{Max: 2}}).All(context.Background())
+	if err != nil {
+		t.Errorf("Users.Pager returned error: %v", err)
+	}
+
+	if len(users) != 5 {
+		t.Errorf("got: %d, want: %d", len(users), 5)
+	}
+}
+
 func TestUsersService_GetByUsername(t *testing.T) {
 	k := client(t)
 
@@ -271,6 +324,86 @@ func TestUsersService_LeaveGroup(t *testing.T) {
 	}
 }
 
+func TestUsersService_ListGroups(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "john")
+
+	ctx := context.Background()
+
+	for _, name := range []string{"admins", "developers", "designers"} {
+		groupID := createGroup(t, k, realm, name)
+		if _, err := k.Users.JoinGroup(ctx, realm, userID, groupID); err != nil {
+			t.Errorf("Users.JoinGroup returned error: %v", err)
+		}
+	}
+
+	groups, res, err := k.Users.ListGroups(ctx, realm, userID, &UserGroupsListOptions{Search: "de"})
+	if err != nil {
+		t.Errorf("Users.ListGroups returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(groups) != 2 {
+		t.Errorf("got: %d, want: %d", len(groups), 2)
+	}
+
+	groups, _, err = k.Users.ListGroups(ctx, realm, userID, &UserGroupsListOptions{Options: Options{Max: 1}})
+	if err != nil {
+		t.Errorf("Users.ListGroups returned error: %v", err)
+	}
+
+	if len(groups) != 1 {
+		t.Errorf("got: %d, want: %d", len(groups), 1)
+	}
+}
+
+func TestUsersService_CountGroups(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "john")
+
+	ctx := context.Background()
+
+	for _, name := range []string{"admins", "developers", "designers"} {
+		groupID := createGroup(t, k, realm, name)
+		if _, err := k.Users.JoinGroup(ctx, realm, userID, groupID); err != nil {
+			t.Errorf("Users.JoinGroup returned error: %v", err)
+		}
+	}
+
+	count, res, err := k.Users.CountGroups(ctx, realm, userID, "")
+	if err != nil {
+		t.Errorf("Users.CountGroups returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if count != 3 {
+		t.Errorf("got: %d, want: %d", count, 3)
+	}
+
+	count, _, err = k.Users.CountGroups(ctx, realm, userID, "admin")
+	if err != nil {
+		t.Errorf("Users.CountGroups returned error: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("got: %d, want: %d", count, 1)
+	}
+}
+
 func TestUsersService_AddRealmRoles(t *testing.T) {
 	k := client(t)
 
@@ -408,6 +541,170 @@ func TestUsersService_RemoveClientRoles(t *testing.T) {
 
 }
 
+// hasRole reports whether roles contain a role with name.
+func hasRole(roles []*Role, name string) bool {
+	for _, role := range roles {
+		if *role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestUsersService_GetRoleMappings(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createRealmRole(t, k, realm, "role")
+	clientID := createClient(t, k, realm, "client")
+	createClientRole(t, k, realm, clientID, "client-role")
+	userID := createUser(t, k, realm, "user")
+
+	ctx := context.Background()
+
+	role, _, err := k.RealmRoles.GetByName(ctx, realm, "role")
+	if err != nil {
+		t.Fatalf("RealmRoles.GetByName returned error: %v", err)
+	}
+	if _, err := k.Users.AddRealmRoles(ctx, realm, userID, []*Role{role}); err != nil {
+		t.Errorf("Users.AddRealmRoles returned error: %v", err)
+	}
+
+	clientRole, _, err := k.ClientRoles.Get(ctx, realm, clientID, "client-role")
+	if err != nil {
+		t.Fatalf("ClientRoles.Get returned error: %v", err)
+	}
+	if _, err := k.Users.AddClientRoles(ctx, realm, userID, clientID, []*Role{clientRole}); err != nil {
+		t.Errorf("Users.AddClientRoles returned error: %v", err)
+	}
+
+	mappings, res, err := k.Users.GetRoleMappings(ctx, realm, userID)
+	if err != nil {
+		t.Fatalf("Users.GetRoleMappings returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	// default-roles-first and role
+	if len(mappings.RealmMappings) != 2 {
+		t.Errorf("got: %d, want: %d", len(mappings.RealmMappings), 2)
+	}
+
+	clientMappings, ok := mappings.ClientMappings["client"]
+	if !ok {
+		t.Fatalf("got: %v, want: mappings of client", mappings.ClientMappings)
+	}
+	if *clientMappings.ID != clientID {
+		t.Errorf("got: %s, want: %s", *clientMappings.ID, clientID)
+	}
+	if !hasRole(clientMappings.Mappings, "client-role") {
+		t.Errorf("got: %v, want: client-role", clientMappings.Mappings)
+	}
+}
+
+func TestUsersService_ListCompositeRealmRoles(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	createRealmRole(t, k, realm, "role")
+	groupID := createGroup(t, k, realm, "group")
+	userID := createUser(t, k, realm, "user")
+
+	ctx := context.Background()
+
+	role, _, err := k.RealmRoles.GetByName(ctx, realm, "role")
+	if err != nil {
+		t.Fatalf("RealmRoles.GetByName returned error: %v", err)
+	}
+	if _, err := k.Groups.AddRealmRoles(ctx, realm, groupID, []*Role{role}); err != nil {
+		t.Errorf("Groups.AddRealmRoles returned error: %v", err)
+	}
+	if _, err := k.Users.JoinGroup(ctx, realm, userID, groupID); err != nil {
+		t.Errorf("Users.JoinGroup returned error: %v", err)
+	}
+
+	roles, res, err := k.Users.ListCompositeRealmRoles(ctx, realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListCompositeRealmRoles returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	// the role of the group and the composites of the default roles
+	for _, name := range []string{"role", "default-roles-first", "offline_access", "uma_authorization"} {
+		if !hasRole(roles, name) {
+			t.Errorf("got: %v, want: %s", roles, name)
+		}
+	}
+
+	roles, _, err = k.Users.ListAvailableRealmRoles(ctx, realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListAvailableRealmRoles returned error: %v", err)
+	}
+
+	if hasRole(roles, "role") || hasRole(roles, "offline_access") {
+		t.Errorf("got: %v, want: no effective roles", roles)
+	}
+}
+
+func TestUsersService_ListClientRoles(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+	clientID := createClient(t, k, realm, "client")
+	createClientRole(t, k, realm, clientID, "role1")
+	createClientRole(t, k, realm, clientID, "role2")
+	userID := createUser(t, k, realm, "user")
+
+	ctx := context.Background()
+
+	role, _, err := k.ClientRoles.Get(ctx, realm, clientID, "role1")
+	if err != nil {
+		t.Fatalf("ClientRoles.Get returned error: %v", err)
+	}
+	if _, err := k.Users.AddClientRoles(ctx, realm, userID, clientID, []*Role{role}); err != nil {
+		t.Errorf("Users.AddClientRoles returned error: %v", err)
+	}
+
+	roles, res, err := k.Users.ListClientRoles(ctx, realm, userID, clientID)
+	if err != nil {
+		t.Errorf("Users.ListClientRoles returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(roles) != 1 {
+		t.Errorf("got: %d, want: %d", len(roles), 1)
+	}
+
+	roles, _, err = k.Users.ListCompositeClientRoles(ctx, realm, userID, clientID)
+	if err != nil {
+		t.Errorf("Users.ListCompositeClientRoles returned error: %v", err)
+	}
+
+	if !hasRole(roles, "role1") || hasRole(roles, "role2") {
+		t.Errorf("got: %v, want: role1", roles)
+	}
+
+	roles, _, err = k.Users.ListAvailableClientRoles(ctx, realm, userID, clientID)
+	if err != nil {
+		t.Errorf("Users.ListAvailableClientRoles returned error: %v", err)
+	}
+
+	if hasRole(roles, "role1") || !hasRole(roles, "role2") {
+		t.Errorf("got: %v, want: role2", roles)
+	}
+}
+
 func TestUsersService_SendVerifyEmail(t *testing.T) {
 	k := client(t)
 
@@ -447,3 +744,324 @@ func TestUsersService_ExecuteActionsEmail(t *testing.T) {
 		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
 	}
 }
+
+func TestUsersService_List_options(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	createUser(t, k, realm, "john")
+	createUser(t, k, realm, "johnny")
+
+	opts := &UsersListOptions{
+		Username: "john",
+		Exact:    Bool(true),
+	}
+	users, _, err := k.Users.List(context.Background(), realm, opts)
+	if err != nil {
+		t.Errorf("Users.List returned error: %v", err)
+	}
+
+	if len(users) != 1 {
+		t.Errorf("got: %d, want: %d", len(users), 1)
+	}
+}
+
+func TestUsersService_Count(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	createUser(t, k, realm, "john")
+	createUser(t, k, realm, "johnny")
+
+	count, res, err := k.Users.Count(context.Background(), realm, &UsersListOptions{Search: "john"})
+	if err != nil {
+		t.Errorf("Users.Count returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if count != 2 {
+		t.Errorf("got: %d, want: %d", count, 2)
+	}
+}
+
+func TestUsersService_GetByUsername_escaped(t *testing.T) {
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("username"); got != "a+b&c" {
+			t.Errorf("got: %s, want: %s", got, "a+b&c")
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	if _, _, err := k.Users.GetByUsername(context.Background(), "first", "a+b&c"); err != nil {
+		t.Errorf("Users.GetByUsername returned error: %v", err)
+	}
+}
+
+// login creates a new session for the user with the password grant.
+func login(t *testing.T, k *Keycloak, realm, userID, username string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	credential := &Credential{
+		Type:      String("password"),
+		Value:     String("secret"),
+		Temporary: Bool(false),
+	}
+	if _, err := k.Users.ResetPassword(ctx, realm, userID, credential); err != nil {
+		t.Errorf("Users.ResetPassword returned error: %v", err)
+	}
+
+	if _, err := NewWithPassword(ctx, "http://localhost:8080/", realm, "admin-cli", username, "secret"); err != nil {
+		t.Errorf("NewWithPassword returned error: %v", err)
+	}
+}
+
+func TestUsersService_Impersonate(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+
+	impersonation, res, err := k.Users.Impersonate(context.Background(), realm, userID)
+	if err != nil {
+		t.Fatalf("Users.Impersonate returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if *impersonation.SameRealm {
+		t.Errorf("got: %t, want: %t", *impersonation.SameRealm, false)
+	}
+
+	if !strings.HasPrefix(*impersonation.Redirect, "http://localhost:8080/realms/first/account") {
+		t.Errorf("got: %s, want: account console of realm first", *impersonation.Redirect)
+	}
+
+	found := false
+	for _, cookie := range impersonation.Cookies {
+		if cookie.Name == "KEYCLOAK_IDENTITY" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("got: %v, want: KEYCLOAK_IDENTITY cookie", impersonation.Cookies)
+	}
+}
+
+func TestUsersService_ListSessions(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+	login(t, k, realm, userID, "user")
+
+	sessions, res, err := k.Users.ListSessions(context.Background(), realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListSessions returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(sessions) != 1 {
+		t.Fatalf("got: %d, want: %d", len(sessions), 1)
+	}
+
+	if *sessions[0].Username != "user" {
+		t.Errorf("got: %s, want: %s", *sessions[0].Username, "user")
+	}
+
+	if _, err := k.Realms.DeleteSession(context.Background(), realm, *sessions[0].ID); err != nil {
+		t.Errorf("Realms.DeleteSession returned error: %v", err)
+	}
+
+	sessions, _, err = k.Users.ListSessions(context.Background(), realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListSessions returned error: %v", err)
+	}
+
+	if len(sessions) != 0 {
+		t.Errorf("got: %d, want: %d", len(sessions), 0)
+	}
+}
+
+func TestUsersService_ListOfflineSessions(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+	clientID := createClient(t, k, realm, "client")
+
+	sessions, res, err := k.Users.ListOfflineSessions(context.Background(), realm, userID, clientID)
+	if err != nil {
+		t.Errorf("Users.ListOfflineSessions returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(sessions) != 0 {
+		t.Errorf("got: %d, want: %d", len(sessions), 0)
+	}
+}
+
+func TestUsersService_ListConsents(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+
+	consents, res, err := k.Users.ListConsents(context.Background(), realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListConsents returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(consents) != 0 {
+		t.Errorf("got: %d, want: %d", len(consents), 0)
+	}
+}
+
+func TestUsersService_RevokeConsent(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+
+	// the user has neither given consent nor has offline tokens
+	_, err := k.Users.RevokeConsent(context.Background(), realm, userID, "account")
+	if !IsNotFound(err) {
+		t.Errorf("got: %v, want: not found", err)
+	}
+}
+
+func TestUsersService_Logout(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+	login(t, k, realm, userID, "user")
+
+	res, err := k.Users.Logout(context.Background(), realm, userID)
+	if err != nil {
+		t.Errorf("Users.Logout returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	sessions, _, err := k.Users.ListSessions(context.Background(), realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListSessions returned error: %v", err)
+	}
+
+	if len(sessions) != 0 {
+		t.Errorf("got: %d, want: %d", len(sessions), 0)
+	}
+}
+
+func TestUsersService_ListCredentials(t *testing.T) {
+	k := client(t)
+
+	realm := "first"
+	createRealm(t, k, realm)
+
+	userID := createUser(t, k, realm, "user")
+
+	credential := &Credential{
+		Type:      String("password"),
+		Value:     String("secret"),
+		Temporary: Bool(false),
+	}
+	if _, err := k.Users.ResetPassword(context.Background(), realm, userID, credential); err != nil {
+		t.Errorf("Users.ResetPassword returned error: %v", err)
+	}
+
+	credentials, res, err := k.Users.ListCredentials(context.Background(), realm, userID)
+	if err != nil {
+		t.Errorf("Users.ListCredentials returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusOK)
+	}
+
+	if len(credentials) != 1 {
+		t.Fatalf("got: %d, want: %d", len(credentials), 1)
+	}
+
+	if *credentials[0].Type != "password" {
+		t.Errorf("got: %s, want: %s", *credentials[0].Type, "password")
+	}
+
+	res, err = k.Users.UpdateCredentialLabel(context.Background(), realm, userID, *credentials[0].ID, "label")
+	if err != nil {
+		t.Errorf("Users.UpdateCredentialLabel returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+
+	if _, err := k.Users.MoveCredentialFirst(context.Background(), realm, userID, *credentials[0].ID); err != nil {
+		t.Errorf("Users.MoveCredentialFirst returned error: %v", err)
+	}
+
+	if _, err := k.Users.DisableCredentialTypes(context.Background(), realm, userID, []string{"otp"}); err != nil {
+		t.Errorf("Users.DisableCredentialTypes returned error: %v", err)
+	}
+
+	res, err = k.Users.DeleteCredential(context.Background(), realm, userID, *credentials[0].ID)
+	if err != nil {
+		t.Errorf("Users.DeleteCredential returned error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Errorf("got: %d, want: %d", res.StatusCode, http.StatusNoContent)
+	}
+}
+
+func TestUsersService_UpdateCredentialLabel_plainText(t *testing.T) {
+	k := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "text/plain" {
+			t.Errorf("got: %s, want: %s", got, "text/plain")
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "my key" {
+			t.Errorf("got: %s, want: %s", body, "my key")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := k.Users.UpdateCredentialLabel(context.Background(), "first", "user", "credential", "my key"); err != nil {
+		t.Errorf("Users.UpdateCredentialLabel returned error: %v", err)
+	}
+}